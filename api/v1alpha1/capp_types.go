@@ -21,6 +21,7 @@ import (
 	nfspvcv1alpha1 "github.com/dana-team/nfspvc-operator/api/v1alpha1"
 	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -69,11 +70,78 @@ type CappSpec struct {
 	// LogSpec defines the configuration for shipping Capp logs.
 	LogSpec LogSpec `json:"logSpec,omitempty"`
 
+	// LogDestinations defines additional named destinations for shipping Capp logs.
+	// The same logs are shipped to LogSpec and to every destination.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	LogDestinations []LogDestination `json:"logDestinations,omitempty"`
+
 	// VolumesSpec defines the volumes specification for the Capp.
 	VolumesSpec VolumesSpec `json:"volumesSpec,omitempty"`
 
 	// Sources define the configuration and status of event sources
 	Sources []KedaSource `json:"sources,omitempty"`
+
+	// RevisionHistory overrides the CappRevision retention policy of the CappConfig for the Capp.
+	// +optional
+	RevisionHistory *RevisionHistoryConfig `json:"revisionHistory,omitempty"`
+
+	// Rollout defines a progressive canary rollout strategy for new revisions of the Capp.
+	// +optional
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+}
+
+// RolloutSpec defines a progressive canary rollout. Every new revision receives an increasing share
+// of the traffic step by step, and is rolled back to the previous ready revision if its analysis fails.
+type RolloutSpec struct {
+	// Steps is the list of steps the new revision goes through before it is promoted.
+	// +kubebuilder:validation:MinItems=1
+	Steps []RolloutStep `json:"steps"`
+
+	// Analysis defines the success criteria which gate the promotion of every step.
+	// +optional
+	Analysis *RolloutAnalysis `json:"analysis,omitempty"`
+}
+
+// RolloutStep defines a single step of a rollout.
+type RolloutStep struct {
+	// Percent is the percentage of the traffic routed to the new revision during the step.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int64 `json:"percent"`
+
+	// Pause is how long to wait at the step before the analysis is evaluated and the next step begins.
+	// +optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+}
+
+// RolloutAnalysis defines the success criteria of a rollout, evaluated against a Prometheus-compatible endpoint.
+type RolloutAnalysis struct {
+	// Address is the URL of a Prometheus-compatible query endpoint, e.g. "http://prometheus.monitoring:9090".
+	Address string `json:"address"`
+
+	// Metrics is a list of metrics which must all be within their thresholds for a step to be promoted.
+	// +kubebuilder:validation:MinItems=1
+	Metrics []RolloutMetric `json:"metrics"`
+}
+
+// RolloutMetric defines a query and the range its result must be within.
+type RolloutMetric struct {
+	// Name is the name of the metric.
+	Name string `json:"name"`
+
+	// Query is a PromQL query which returns a single value. It is a Go template which may reference
+	// {{ .Revision }}, {{ .StableRevision }}, {{ .Namespace }} and {{ .CappName }}.
+	Query string `json:"query"`
+
+	// Min is the minimum allowed value of the query result, e.g. "0.99".
+	// +optional
+	Min string `json:"min,omitempty"`
+
+	// Max is the maximum allowed value of the query result, e.g. "0.01".
+	// +optional
+	Max string `json:"max,omitempty"`
 }
 
 // VolumesSpec defines the volumes specification for the Capp.
@@ -107,39 +175,165 @@ type RouteSpec struct {
 	// +optional
 	TlsEnabled bool `json:"tlsEnabled,omitempty"`
 
+	// AdditionalHostnames is a list of custom DNS names the Capp route answers on in addition to Hostname.
+	// It can only be set together with Hostname.
+	// +optional
+	AdditionalHostnames []HostnameSpec `json:"additionalHostnames,omitempty"`
+
 	// TrafficTarget holds a single entry of the routing table for the Capp route.
+	// It is ignored when TrafficTargets is set.
 	// +optional
 	TrafficTarget knativev1.TrafficTarget `json:"trafficTarget,omitempty"`
 
+	// TrafficTargets holds the routing table for the Capp route. It splits the traffic between the
+	// latest revision and pinned revisions by percentage, and can expose revisions under tags.
+	// The percentages must sum to 100.
+	// +optional
+	TrafficTargets []knativev1.TrafficTarget `json:"trafficTargets,omitempty"`
+
 	// RouteTimeoutSeconds is the maximum duration in seconds
 	// that the request instance is allowed to respond to a request.
 	// +optional
 	RouteTimeoutSeconds *int64 `json:"routeTimeoutSeconds,omitempty"`
 }
 
+// HostnameSpec defines a custom DNS name of the Capp route.
+type HostnameSpec struct {
+	// Hostname is a custom DNS name for the Capp route.
+	Hostname string `json:"hostname"`
+
+	// TlsEnabled determines whether to enable TLS for the hostname.
+	// +optional
+	TlsEnabled bool `json:"tlsEnabled,omitempty"`
+}
+
 // LogSpec defines the configuration for shipping Capp logs.
 type LogSpec struct {
 	// Type defines where to send the Capp logs
-	// +kubebuilder:validation:Enum=elastic
+	// +kubebuilder:validation:Enum=elastic;splunk;loki;kafka;syslog
 	// +optional
 	Type string `json:"type,omitempty"`
 
-	// Host defines Elasticsearch or Splunk host.
+	// Host defines the URL of Elasticsearch, the Splunk HEC endpoint, Loki or the Kafka REST Proxy,
+	// or the address of the syslog server.
 	// +optional
 	Host string `json:"host,omitempty"`
 
-	// Index defines the index name to write events to.
+	// Index defines the Elasticsearch or Splunk index name to write events to.
+	// The Elasticsearch index is a template with the placeholders {{ .Namespace }}, {{ .CappName }},
+	// {{ .NamespaceLabels.<key> }} and the date of the event {{ .Date }}, {{ .Year }}, {{ .Month }} and {{ .Day }},
+	// e.g. "{{ .NamespaceLabels.team }}-{{ .Date }}". It defaults to the default index of the CappConfig.
 	// +optional
 	Index string `json:"index,omitempty"`
 
+	// Topic defines the Kafka topic to write events to.
+	// +optional
+	Topic string `json:"topic,omitempty"`
+
+	// Port defines the port of the syslog server.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Transport defines the transport protocol of the syslog server.
+	// +kubebuilder:validation:Enum=tcp;udp;tls
+	// +optional
+	Transport string `json:"transport,omitempty"`
+
 	// User defines a User for authentication.
 	// +optional
 	User string `json:"user,omitempty"`
 
 	// PasswordSecret defines the name of the secret
-	// containing the password for authentication.
+	// containing the password for authentication, or the HEC token for Splunk.
 	// +optional
 	PasswordSecret string `json:"passwordSecret,omitempty"`
+
+	// PasswordSecretKey defines the key of the password in the password secret.
+	// Defaults to the type of the log backend, e.g. elastic.
+	// +optional
+	PasswordSecretKey string `json:"passwordSecretKey,omitempty"`
+
+	// TLS defines the TLS configuration of the connection to the log backend.
+	// It is not supported for Loki.
+	// +optional
+	TLS LogTLS `json:"tls,omitempty"`
+
+	// Filters defines which log lines are shipped.
+	// +optional
+	Filters *LogFilters `json:"filters,omitempty"`
+
+	// Parser defines how log lines are parsed into fields before they are shipped.
+	// +optional
+	Parser *LogParser `json:"parser,omitempty"`
+}
+
+// LogFilters defines which log lines are shipped.
+type LogFilters struct {
+	// Include defines regular expressions of which a log line must match at least one to be shipped.
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude defines regular expressions of which a log line must match none to be shipped.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// MinSeverity defines the lowest severity of the log lines which are shipped, detected by the level keyword
+	// which leads the line, e.g. "ERROR ...", or is the value of a level, lvl or severity field, e.g. "level":"error"
+	// or level=error. Log lines without a level are not shipped when it is set.
+	// +kubebuilder:validation:Enum=info;warning;error;critical
+	// +optional
+	MinSeverity string `json:"minSeverity,omitempty"`
+}
+
+// LogParser defines how log lines are parsed into fields.
+type LogParser struct {
+	// Patterns defines regular expressions with named capture groups, e.g. "level":"(?<level>[^"]+)".
+	// Every named group of the first matching pattern becomes a field of the shipped log.
+	// The log lines are matched as text and not decoded as JSON, so escaped characters are not unescaped,
+	// nested objects are not flattened and the keys must appear in the order of the pattern.
+	// +kubebuilder:validation:MinItems=1
+	Patterns []string `json:"patterns"`
+
+	// Prefix defines the prefix of the names of the parsed fields. Defaults to "json#fields#",
+	// which nests the parsed fields under the "fields" key of the shipped log.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// LogTLS defines the TLS configuration of the connection to the log backend.
+type LogTLS struct {
+	// PeerVerify defines whether the certificate of the log backend is verified. Defaults to true.
+	// +optional
+	PeerVerify *bool `json:"peerVerify,omitempty"`
+
+	// CASecret defines the name of the secret containing the CA bundle
+	// which is used to verify the certificate of the log backend, under the ca.crt key.
+	// +optional
+	CASecret string `json:"caSecret,omitempty"`
+
+	// ClientCertSecret defines the name of the secret containing the client certificate and key
+	// for mutual TLS, under the tls.crt and tls.key keys.
+	// +optional
+	ClientCertSecret string `json:"clientCertSecret,omitempty"`
+
+	// SSLVersion defines the TLS version of the connection. Defaults to tlsv1_2.
+	// +kubebuilder:validation:Enum=tlsv1_2;tlsv1_3
+	// +optional
+	SSLVersion string `json:"sslVersion,omitempty"`
+}
+
+// LogDestination defines a named destination for shipping Capp logs.
+type LogDestination struct {
+	// Name is the name of the destination, which is unique within the Capp.
+	// The SyslogNGFlow and SyslogNGOutput of the destination are named <capp>-<name>.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	LogSpec `json:",inline"`
 }
 
 // ApplicationLinks contains relevant information about
@@ -175,6 +369,58 @@ type StateStatus struct {
 	LastChange metav1.Time `json:"lastChange,omitempty"`
 }
 
+// RolloutStatus defines the progress of the latest rollout of the Capp.
+type RolloutStatus struct {
+	// Phase is the phase of the rollout.
+	// +kubebuilder:validation:Enum=Progressing;Succeeded;Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// StableRevision is the revision which receives the traffic that is not routed to the canary revision.
+	// +optional
+	StableRevision string `json:"stableRevision,omitempty"`
+
+	// CanaryRevision is the revision which is being rolled out.
+	// +optional
+	CanaryRevision string `json:"canaryRevision,omitempty"`
+
+	// CurrentStep is the index of the current step of the rollout.
+	// +optional
+	CurrentStep int `json:"currentStep,omitempty"`
+
+	// CanaryPercent is the percentage of the traffic currently routed to the canary revision.
+	// +optional
+	CanaryPercent int64 `json:"canaryPercent,omitempty"`
+
+	// StepStartTime is the time the current step began.
+	// +optional
+	StepStartTime metav1.Time `json:"stepStartTime,omitempty"`
+
+	// Message contains details about the current state of the rollout.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RollbackStatus defines the result of the latest rollback of the Capp to a CappRevision.
+type RollbackStatus struct {
+	// RevisionNumber is the number of the CappRevision the Capp was requested to roll back to.
+	// +optional
+	RevisionNumber int `json:"revisionNumber,omitempty"`
+
+	// Result is the result of the latest rollback.
+	// +kubebuilder:validation:Enum=Succeeded;Failed
+	// +optional
+	Result string `json:"result,omitempty"`
+
+	// Message contains details about the result of the latest rollback.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastRollbackTime is the last time a rollback was attempted.
+	// +optional
+	LastRollbackTime metav1.Time `json:"lastRollbackTime,omitempty"`
+}
+
 // LoggingStatus defines the state of the SyslogNGFlow and SyslogNGOutput objects linked to the Capp.
 type LoggingStatus struct {
 	// SyslogNGFlow represents the Status of the SyslogNGFlow used by the Capp.
@@ -185,11 +431,32 @@ type LoggingStatus struct {
 	// +optional
 	SyslogNGOutput loggingv1beta1.SyslogNGOutputStatus `json:"syslogngoutput,omitempty"`
 
+	// Destinations represents the Status of the SyslogNGFlows and SyslogNGOutputs of the log destinations of the Capp.
+	// +optional
+	Destinations []LogDestinationStatus `json:"destinations,omitempty"`
+
 	// Conditions contain details about the current state of the SyslogNGFlow and SyslogNGOutput used by the Capp.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// LogDestinationStatus defines the state of the SyslogNGFlow and SyslogNGOutput of a log destination of the Capp.
+type LogDestinationStatus struct {
+	// Name is the name of the log destination.
+	Name string `json:"name"`
+
+	// Output is the name of the SyslogNGOutput and the SyslogNGFlow of the log destination.
+	Output string `json:"output"`
+
+	// SyslogNGFlow represents the Status of the SyslogNGFlow of the log destination.
+	// +optional
+	SyslogNGFlow loggingv1beta1.SyslogNGFlowStatus `json:"syslogngflow,omitempty"`
+
+	// SyslogNGOutput represents the Status of the SyslogNGOutput of the log destination.
+	// +optional
+	SyslogNGOutput loggingv1beta1.SyslogNGOutputStatus `json:"syslogngoutput,omitempty"`
+}
+
 // RouteStatus shows the state of the DomainMapping object linked to the Capp.
 type RouteStatus struct {
 	// DomainMappingObjectStatus is the status of the underlying DomainMapping object
@@ -203,6 +470,50 @@ type RouteStatus struct {
 	// CertificateObjectStatus is the status of the underlying Certificate object
 	// +optional
 	CertificateObjectStatus cmapi.CertificateStatus `json:"certificateObjectStatus,omitempty"`
+
+	// PreviewURLs lists the revisions which receive no traffic and the URLs they are exposed on. Untagged revisions
+	// are exposed under a generated tag: the revision name, or "latest" for the latest revision.
+	// +optional
+	PreviewURLs []PreviewURL `json:"previewURLs,omitempty"`
+
+	// HostnamesStatus is the status of the underlying objects of every custom hostname of the Capp route.
+	// +optional
+	HostnamesStatus []HostnameStatus `json:"hostnamesStatus,omitempty"`
+}
+
+// HostnameStatus shows the state of the objects of a custom hostname of the Capp route.
+type HostnameStatus struct {
+	// Hostname is the custom hostname.
+	Hostname string `json:"hostname"`
+
+	// DomainMappingObjectStatus is the status of the underlying DomainMapping object
+	// +optional
+	DomainMappingObjectStatus knativev1beta1.DomainMappingStatus `json:"domainMappingObjectStatus,omitempty"`
+
+	// DNSRecordObjectStatus is the status of the underlying DNSRecord object
+	// +optional
+	DNSRecordObjectStatus DNSRecordObjectStatus `json:"dnsRecordObjectStatus,omitempty"`
+
+	// CertificateObjectStatus is the status of the underlying Certificate object
+	// +optional
+	CertificateObjectStatus cmapi.CertificateStatus `json:"certificateObjectStatus,omitempty"`
+}
+
+// PreviewURL shows the URL on which a tagged revision of the Capp is exposed for testing before it is promoted.
+type PreviewURL struct {
+	// Tag is the tag of the revision.
+	Tag string `json:"tag"`
+
+	// RevisionName is the name of the revision. It is empty if the tag follows the latest revision.
+	// +optional
+	RevisionName string `json:"revisionName,omitempty"`
+
+	// URL is the preview URL, in the form of <tag>-<hostname>.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Ready indicates whether the DomainMapping of the preview is ready.
+	Ready bool `json:"ready"`
 }
 
 type DNSRecordObjectStatus struct {
@@ -231,8 +542,10 @@ type KedaSource struct {
 	// Name is the name of the Keda source
 	Name string `json:"name"`
 
-	// ScalarType defines the type of the scalar used
-	ScalarType string `json:"scalarType"`
+	// ScalarType defines the type of the scalar used.
+	// It is used to define a single trigger and is ignored when Triggers is set.
+	// +optional
+	ScalarType string `json:"scalarType,omitempty"`
 
 	// ScalarMetadata defines the data passed directly to the scalar
 	ScalarMetadata map[string]string `json:"scalarMetadata,omitempty"`
@@ -240,11 +553,77 @@ type KedaSource struct {
 	// TriggerAuth defines the authentication for the trigger (if needed)
 	TriggerAuth *TriggerAuth `json:"triggerAuth,omitempty"`
 
+	// Triggers is a list of triggers the source scales on.
+	// When set, it takes precedence over ScalarType, ScalarMetadata and TriggerAuth.
+	// +optional
+	Triggers []KedaTrigger `json:"triggers,omitempty"`
+
 	// MinReplicas is the minimum of replicas allowed
 	MinReplicas *int32 `json:"minReplicas,omitempty"`
 
 	// MaxReplicas is the maximum of replicas allowed
 	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// Advanced defines advanced scaling options of the source.
+	// +optional
+	Advanced *KedaAdvanced `json:"advanced,omitempty"`
+}
+
+// KedaTrigger defines a single trigger of a Keda source
+type KedaTrigger struct {
+	// Name is the name of the trigger. It is needed to reference the trigger in a scaling modifiers formula.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Type defines the type of the scalar used
+	Type string `json:"type"`
+
+	// Metadata defines the data passed directly to the scalar
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// TriggerAuth defines the authentication for the trigger (if needed)
+	// +optional
+	TriggerAuth *TriggerAuth `json:"triggerAuth,omitempty"`
+}
+
+// KedaAdvanced defines advanced scaling options of a Keda source
+type KedaAdvanced struct {
+	// ScalingModifiers defines a formula which combines the metrics of the triggers into a single metric.
+	// +optional
+	ScalingModifiers *ScalingModifiers `json:"scalingModifiers,omitempty"`
+
+	// Behavior configures the scaling behavior of the underlying HPA in both Up and Down directions.
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+
+	// PollingInterval is the interval in seconds to check each trigger on.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PollingInterval *int32 `json:"pollingInterval,omitempty"`
+
+	// CooldownPeriod is the period in seconds to wait after the last trigger reported active before scaling to zero.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+}
+
+// ScalingModifiers defines a formula used to compose the metrics of several triggers
+type ScalingModifiers struct {
+	// Formula is the expression composing the trigger metrics, referenced by trigger name.
+	Formula string `json:"formula"`
+
+	// Target is the target value of the composed metric.
+	Target string `json:"target"`
+
+	// ActivationTarget is the activation value of the composed metric.
+	// +optional
+	ActivationTarget string `json:"activationTarget,omitempty"`
+
+	// MetricType is the type of the composed metric.
+	// +kubebuilder:validation:Enum=AverageValue;Value
+	// +optional
+	MetricType autoscalingv2.MetricTargetType `json:"metricType,omitempty"`
 }
 
 // TriggerAuth defines the authentication info needed for a scalar trigger
@@ -269,6 +648,9 @@ type TriggerAuth struct {
 
 // KedaStatus defines the status of a Keda source
 type KedaStatus struct {
+	// Name is the name of the Keda source the status belongs to.
+	Name string `json:"name"`
+
 	// Conditions contain details about the current state of the Keda source.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -305,18 +687,61 @@ type CappStatus struct {
 	// +optional
 	VolumesStatus VolumesStatus `json:"volumesStatus,omitempty"`
 
-	// Conditions contain details about the current state of the Capp.
+	// Conditions contain details about the current state of the Capp. The Ready condition combines the
+	// KnativeServiceReady, RouteReady, DNSReady, CertificateReady, LoggingReady, VolumesReady and SourcesReady conditions.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// ObservedGeneration is the generation of the Capp that the status was last computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// CappConfigGenerations lists the generations of the CappConfigs that the Capp was last reconciled against:
+	// the cluster CappConfig followed by the overrides which select the namespace of the Capp, in the order they are applied.
+	// +optional
+	CappConfigGenerations []CappConfigGeneration `json:"cappConfigGenerations,omitempty"`
+
 	// SourceStatus contains details about the current state of a source.
+	// +optional
 	SourceStatus []KedaStatus `json:"sourceStatus,omitempty"`
+
+	// RollbackStatus contains details about the latest rollback of the Capp.
+	// +optional
+	RollbackStatus RollbackStatus `json:"rollbackStatus,omitempty"`
+
+	// Rollout contains details about the progress of the latest rollout of the Capp.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// BlockedResources lists the resources which were not reconciled in the latest reconciliation,
+	// because resources they depend on are not ready yet.
+	// +optional
+	BlockedResources []BlockedResource `json:"blockedResources,omitempty"`
+}
+
+// CappConfigGeneration is the generation of a CappConfig that a Capp was reconciled against.
+type CappConfigGeneration struct {
+	// Name is the name of the CappConfig.
+	Name string `json:"name"`
+
+	// Generation is the generation of the CappConfig.
+	Generation int64 `json:"generation"`
+}
+
+// BlockedResource describes a resource of the Capp whose reconciliation waits for other resources.
+type BlockedResource struct {
+	// Name is the name of the blocked resource manager, e.g. "domainMapping".
+	Name string `json:"name"`
+
+	// WaitingFor lists the names of the resource managers whose resources are not ready yet.
+	WaitingFor []string `json:"waitingFor"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:printcolumn:name="Site",type="string",JSONPath=".status.applicationLinks.site",description="cluster of the resource"
 // +kubebuilder:printcolumn:name="Custom URL",type="string",JSONPath=".spec.routeSpec.hostname",description="shorten url"
 // +kubebuilder:printcolumn:name="AutoScale Type",type="string",JSONPath=".spec.scaleMetric",description="autoscale metric"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="whether the Capp is ready"
 // +kubebuilder:subresource:status
 
 // Capp is the Schema for the capps API.
@@ -5,52 +5,116 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// CappConfigSpec defines the desired state of CappConfig
+// CappConfigSpec defines the desired state of CappConfig.
+// The fields are optional so that a CappConfig which overrides the cluster CappConfig can set only some of them.
 type CappConfigSpec struct {
-	// +kubebuilder:validation:Required
+	// NamespaceSelector makes the CappConfig an override of the cluster CappConfig for the Capps in the namespaces
+	// it selects. Every field which is set in the override replaces the value of the cluster CappConfig.
+	// Overrides are only read from the namespace of the operator, and are applied in the order of their names.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// +optional
 	DNSConfig DNSConfig `json:"dnsConfig"`
 
-	// +kubebuilder:validation:Required
+	// +optional
 	AutoscaleConfig AutoscaleConfig `json:"autoscaleConfig"`
 
+	// +optional
+	LogConfig LogConfig `json:"logConfig,omitempty"`
+
 	// DefaultResources is the default resources to be assigned to Capp.
 	// If other resources are specified then they override the default values.
+	// +optional
 	DefaultResources corev1.ResourceRequirements `json:"defaultResources"`
 
 	// AllowedHostnamePatterns is an optional slice of regex patterns to be used to validate the hostname of the Capp.
 	// If the Capp hostname matches a pattern, it is allowed to be created.
 	// +kubebuilder:default:={}
+	// +optional
 	AllowedHostnamePatterns []string `json:"allowedHostnamePatterns"`
+
+	// RevisionHistory is the default CappRevision retention policy of Capps.
+	// +optional
+	RevisionHistory *RevisionHistoryConfig `json:"revisionHistory,omitempty"`
 }
 
 type DNSConfig struct {
 	// Zone defines the DNS zone for Capp Hostnames
+	// +optional
 	Zone string `json:"zone"`
 	// CNAME defines the CNAME record that will be used for Capp Hostnames
+	// +optional
 	CNAME string `json:"cname"`
 	// Provider defines the DNS provider
+	// +optional
 	Provider string `json:"provider"`
 	// Issuer defines the certificate issuer
+	// +optional
 	Issuer string `json:"issuer"`
 }
 
+// RevisionHistoryConfig defines how many CappRevisions are kept for a Capp and for how long.
+// CappRevisions are pruned by count or by age, whichever limit is hit first.
+type RevisionHistoryConfig struct {
+	// Limit is the maximum number of CappRevisions kept for a Capp.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Limit *int `json:"limit,omitempty"`
+	// MaxAge is the maximum age of a CappRevision, e.g. "720h". Older CappRevisions are pruned.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+type LogConfig struct {
+	// DefaultIndex defines the index of the Elasticsearch log destinations of Capps which do not set one.
+	// It is a template with the same placeholders as the index of a Capp, e.g. "{{ .Namespace }}-{{ .Date }}".
+	// +optional
+	DefaultIndex string `json:"defaultIndex,omitempty"`
+}
+
 type AutoscaleConfig struct {
 	// RPS is the desired requests per second to trigger upscaling.
+	// +optional
 	RPS int `json:"rps"`
 	// CPU is the desired CPU utilization to trigger upscaling.
+	// +optional
 	CPU int `json:"cpu"`
 	// Memory is the desired memory utilization to trigger upscaling.
+	// +optional
 	Memory int `json:"memory"`
 	// Concurrency is the maximum concurrency of a Capp.
+	// +optional
 	Concurrency int `json:"concurrency"`
 	// ActivationScale is the default scale.
+	// +optional
 	ActivationScale int `json:"activationScale"`
 }
 
 // CappConfigStatus defines the observed state of CappConfig
-type CappConfigStatus struct{}
+type CappConfigStatus struct {
+	// Conditions reflect whether the CappConfig is valid and whether the resources it references exist.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the CappConfig that the status was last computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Namespaces is the number of namespaces with Capps which use the CappConfig.
+	// +optional
+	Namespaces int `json:"namespaces,omitempty"`
+
+	// Capps is the number of Capps which use the CappConfig.
+	// +optional
+	Capps int `json:"capps,omitempty"`
+}
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="whether the CappConfig is valid and its references exist"
+// +kubebuilder:printcolumn:name="Capps",type="integer",JSONPath=".status.capps",description="number of Capps which use the CappConfig"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // CappConfig is the Schema for the cappconfigs API
 type CappConfig struct {
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,7 +31,38 @@ type CappRevisionSpec struct {
 }
 
 // CappRevisionStatus defines the observed state of CappRevision
-type CappRevisionStatus struct{}
+type CappRevisionStatus struct {
+	// Author is the user who made the change recorded in the CappRevision.
+	// It is taken from the last-updated-by annotation of the Capp.
+	// +optional
+	Author string `json:"author,omitempty"`
+
+	// ChangeCause is the reason of the change recorded in the CappRevision.
+	// It is taken from the change-cause annotation of the Capp.
+	// +optional
+	ChangeCause string `json:"changeCause,omitempty"`
+
+	// PreviousRevisionNumber is the revision number of the CappRevision the diff was computed against.
+	// +optional
+	PreviousRevisionNumber int `json:"previousRevisionNumber,omitempty"`
+
+	// Diff is a JSON patch which transforms the CappTemplate of the previous CappRevision into the CappTemplate of this one.
+	// +optional
+	Diff []JSONPatchOperation `json:"diff,omitempty"`
+}
+
+// JSONPatchOperation is a single operation of a JSON patch, as defined in RFC 6902.
+type JSONPatchOperation struct {
+	// Operation is the JSON patch operation, e.g. "add", "remove" or "replace".
+	Operation string `json:"op"`
+
+	// Path is the JSON pointer to the changed field.
+	Path string `json:"path"`
+
+	// Value is the new value of the field. It is empty for "remove" operations.
+	// +optional
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
+}
 
 // CappTemplate template of Capp.
 type CappTemplate struct {
@@ -48,6 +80,10 @@ type CappTemplate struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".spec.revisionNumber",description="revision number"
+// +kubebuilder:printcolumn:name="Author",type="string",JSONPath=".status.author",description="user who made the change"
+// +kubebuilder:printcolumn:name="Change Cause",type="string",JSONPath=".status.changeCause",description="reason of the change"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // CappRevision is the Schema for the CappRevisions API
 type CappRevision struct {
@@ -21,9 +21,12 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -87,6 +90,26 @@ func (in *AutoscaleConfig) DeepCopy() *AutoscaleConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlockedResource) DeepCopyInto(out *BlockedResource) {
+	*out = *in
+	if in.WaitingFor != nil {
+		in, out := &in.WaitingFor, &out.WaitingFor
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockedResource.
+func (in *BlockedResource) DeepCopy() *BlockedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(BlockedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Capp) DeepCopyInto(out *Capp) {
 	*out = *in
@@ -120,7 +143,7 @@ func (in *CappConfig) DeepCopyInto(out *CappConfig) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappConfig.
@@ -141,6 +164,21 @@ func (in *CappConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CappConfigGeneration) DeepCopyInto(out *CappConfigGeneration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappConfigGeneration.
+func (in *CappConfigGeneration) DeepCopy() *CappConfigGeneration {
+	if in == nil {
+		return nil
+	}
+	out := new(CappConfigGeneration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CappConfigList) DeepCopyInto(out *CappConfigList) {
 	*out = *in
@@ -176,14 +214,25 @@ func (in *CappConfigList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CappConfigSpec) DeepCopyInto(out *CappConfigSpec) {
 	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	out.DNSConfig = in.DNSConfig
 	out.AutoscaleConfig = in.AutoscaleConfig
+	out.LogConfig = in.LogConfig
 	in.DefaultResources.DeepCopyInto(&out.DefaultResources)
 	if in.AllowedHostnamePatterns != nil {
 		in, out := &in.AllowedHostnamePatterns, &out.AllowedHostnamePatterns
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RevisionHistory != nil {
+		in, out := &in.RevisionHistory, &out.RevisionHistory
+		*out = new(RevisionHistoryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappConfigSpec.
@@ -199,6 +248,13 @@ func (in *CappConfigSpec) DeepCopy() *CappConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CappConfigStatus) DeepCopyInto(out *CappConfigStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappConfigStatus.
@@ -249,7 +305,7 @@ func (in *CappRevision) DeepCopyInto(out *CappRevision) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRevision.
@@ -321,6 +377,13 @@ func (in *CappRevisionSpec) DeepCopy() *CappRevisionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CappRevisionStatus) DeepCopyInto(out *CappRevisionStatus) {
 	*out = *in
+	if in.Diff != nil {
+		in, out := &in.Diff, &out.Diff
+		*out = make([]JSONPatchOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappRevisionStatus.
@@ -338,7 +401,14 @@ func (in *CappSpec) DeepCopyInto(out *CappSpec) {
 	*out = *in
 	in.ConfigurationSpec.DeepCopyInto(&out.ConfigurationSpec)
 	in.RouteSpec.DeepCopyInto(&out.RouteSpec)
-	out.LogSpec = in.LogSpec
+	in.LogSpec.DeepCopyInto(&out.LogSpec)
+	if in.LogDestinations != nil {
+		in, out := &in.LogDestinations, &out.LogDestinations
+		*out = make([]LogDestination, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.VolumesSpec.DeepCopyInto(&out.VolumesSpec)
 	if in.Sources != nil {
 		in, out := &in.Sources, &out.Sources
@@ -347,6 +417,16 @@ func (in *CappSpec) DeepCopyInto(out *CappSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RevisionHistory != nil {
+		in, out := &in.RevisionHistory, &out.RevisionHistory
+		*out = new(RevisionHistoryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappSpec.
@@ -377,11 +457,16 @@ func (in *CappStatus) DeepCopyInto(out *CappStatus) {
 	in.VolumesStatus.DeepCopyInto(&out.VolumesStatus)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CappConfigGenerations != nil {
+		in, out := &in.CappConfigGenerations, &out.CappConfigGenerations
+		*out = make([]CappConfigGeneration, len(*in))
+		copy(*out, *in)
+	}
 	if in.SourceStatus != nil {
 		in, out := &in.SourceStatus, &out.SourceStatus
 		*out = make([]KedaStatus, len(*in))
@@ -389,6 +474,19 @@ func (in *CappStatus) DeepCopyInto(out *CappStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.RollbackStatus.DeepCopyInto(&out.RollbackStatus)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BlockedResources != nil {
+		in, out := &in.BlockedResources, &out.BlockedResources
+		*out = make([]BlockedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CappStatus.
@@ -462,6 +560,94 @@ func (in *DNSRecordObjectStatus) DeepCopy() *DNSRecordObjectStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameSpec) DeepCopyInto(out *HostnameSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameSpec.
+func (in *HostnameSpec) DeepCopy() *HostnameSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameStatus) DeepCopyInto(out *HostnameStatus) {
+	*out = *in
+	in.DomainMappingObjectStatus.DeepCopyInto(&out.DomainMappingObjectStatus)
+	in.DNSRecordObjectStatus.DeepCopyInto(&out.DNSRecordObjectStatus)
+	in.CertificateObjectStatus.DeepCopyInto(&out.CertificateObjectStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameStatus.
+func (in *HostnameStatus) DeepCopy() *HostnameStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPatchOperation) DeepCopyInto(out *JSONPatchOperation) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPatchOperation.
+func (in *JSONPatchOperation) DeepCopy() *JSONPatchOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPatchOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KedaAdvanced) DeepCopyInto(out *KedaAdvanced) {
+	*out = *in
+	if in.ScalingModifiers != nil {
+		in, out := &in.ScalingModifiers, &out.ScalingModifiers
+		*out = new(ScalingModifiers)
+		**out = **in
+	}
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(v2.HorizontalPodAutoscalerBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KedaAdvanced.
+func (in *KedaAdvanced) DeepCopy() *KedaAdvanced {
+	if in == nil {
+		return nil
+	}
+	out := new(KedaAdvanced)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KedaSource) DeepCopyInto(out *KedaSource) {
 	*out = *in
@@ -477,6 +663,13 @@ func (in *KedaSource) DeepCopyInto(out *KedaSource) {
 		*out = new(TriggerAuth)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]KedaTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.MinReplicas != nil {
 		in, out := &in.MinReplicas, &out.MinReplicas
 		*out = new(int32)
@@ -487,6 +680,11 @@ func (in *KedaSource) DeepCopyInto(out *KedaSource) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Advanced != nil {
+		in, out := &in.Advanced, &out.Advanced
+		*out = new(KedaAdvanced)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KedaSource.
@@ -504,7 +702,7 @@ func (in *KedaStatus) DeepCopyInto(out *KedaStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -521,9 +719,140 @@ func (in *KedaStatus) DeepCopy() *KedaStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KedaTrigger) DeepCopyInto(out *KedaTrigger) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TriggerAuth != nil {
+		in, out := &in.TriggerAuth, &out.TriggerAuth
+		*out = new(TriggerAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KedaTrigger.
+func (in *KedaTrigger) DeepCopy() *KedaTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(KedaTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogConfig) DeepCopyInto(out *LogConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogConfig.
+func (in *LogConfig) DeepCopy() *LogConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LogConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogDestination) DeepCopyInto(out *LogDestination) {
+	*out = *in
+	in.LogSpec.DeepCopyInto(&out.LogSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogDestination.
+func (in *LogDestination) DeepCopy() *LogDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(LogDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogDestinationStatus) DeepCopyInto(out *LogDestinationStatus) {
+	*out = *in
+	in.SyslogNGFlow.DeepCopyInto(&out.SyslogNGFlow)
+	in.SyslogNGOutput.DeepCopyInto(&out.SyslogNGOutput)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogDestinationStatus.
+func (in *LogDestinationStatus) DeepCopy() *LogDestinationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogDestinationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogFilters) DeepCopyInto(out *LogFilters) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogFilters.
+func (in *LogFilters) DeepCopy() *LogFilters {
+	if in == nil {
+		return nil
+	}
+	out := new(LogFilters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogParser) DeepCopyInto(out *LogParser) {
+	*out = *in
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogParser.
+func (in *LogParser) DeepCopy() *LogParser {
+	if in == nil {
+		return nil
+	}
+	out := new(LogParser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogSpec) DeepCopyInto(out *LogSpec) {
 	*out = *in
+	in.TLS.DeepCopyInto(&out.TLS)
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = new(LogFilters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Parser != nil {
+		in, out := &in.Parser, &out.Parser
+		*out = new(LogParser)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogSpec.
@@ -536,14 +865,41 @@ func (in *LogSpec) DeepCopy() *LogSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogTLS) DeepCopyInto(out *LogTLS) {
+	*out = *in
+	if in.PeerVerify != nil {
+		in, out := &in.PeerVerify, &out.PeerVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogTLS.
+func (in *LogTLS) DeepCopy() *LogTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(LogTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoggingStatus) DeepCopyInto(out *LoggingStatus) {
 	*out = *in
 	in.SyslogNGFlow.DeepCopyInto(&out.SyslogNGFlow)
 	in.SyslogNGOutput.DeepCopyInto(&out.SyslogNGOutput)
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]LogDestinationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -565,7 +921,7 @@ func (in *NFSVolume) DeepCopyInto(out *NFSVolume) {
 	*out = *in
 	if in.Capacity != nil {
 		in, out := &in.Capacity, &out.Capacity
-		*out = make(v1.ResourceList, len(*in))
+		*out = make(corev1.ResourceList, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val.DeepCopy()
 		}
@@ -613,6 +969,46 @@ func (in *PodIdentity) DeepCopy() *PodIdentity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewURL) DeepCopyInto(out *PreviewURL) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewURL.
+func (in *PreviewURL) DeepCopy() *PreviewURL {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewURL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionHistoryConfig) DeepCopyInto(out *RevisionHistoryConfig) {
+	*out = *in
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionHistoryConfig.
+func (in *RevisionHistoryConfig) DeepCopy() *RevisionHistoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionHistoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RevisionInfo) DeepCopyInto(out *RevisionInfo) {
 	*out = *in
@@ -629,10 +1025,136 @@ func (in *RevisionInfo) DeepCopy() *RevisionInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackStatus) DeepCopyInto(out *RollbackStatus) {
+	*out = *in
+	in.LastRollbackTime.DeepCopyInto(&out.LastRollbackTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackStatus.
+func (in *RollbackStatus) DeepCopy() *RollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutAnalysis) DeepCopyInto(out *RolloutAnalysis) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]RolloutMetric, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutAnalysis.
+func (in *RolloutAnalysis) DeepCopy() *RolloutAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutAnalysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutMetric) DeepCopyInto(out *RolloutMetric) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutMetric.
+func (in *RolloutMetric) DeepCopy() *RolloutMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RolloutStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(RolloutAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutSpec.
+func (in *RolloutSpec) DeepCopy() *RolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	in.StepStartTime.DeepCopyInto(&out.StepStartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStep) DeepCopyInto(out *RolloutStep) {
+	*out = *in
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStep.
+func (in *RolloutStep) DeepCopy() *RolloutStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
 	*out = *in
+	if in.AdditionalHostnames != nil {
+		in, out := &in.AdditionalHostnames, &out.AdditionalHostnames
+		*out = make([]HostnameSpec, len(*in))
+		copy(*out, *in)
+	}
 	in.TrafficTarget.DeepCopyInto(&out.TrafficTarget)
+	if in.TrafficTargets != nil {
+		in, out := &in.TrafficTargets, &out.TrafficTargets
+		*out = make([]servingv1.TrafficTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.RouteTimeoutSeconds != nil {
 		in, out := &in.RouteTimeoutSeconds, &out.RouteTimeoutSeconds
 		*out = new(int64)
@@ -656,6 +1178,18 @@ func (in *RouteStatus) DeepCopyInto(out *RouteStatus) {
 	in.DomainMappingObjectStatus.DeepCopyInto(&out.DomainMappingObjectStatus)
 	in.DNSRecordObjectStatus.DeepCopyInto(&out.DNSRecordObjectStatus)
 	in.CertificateObjectStatus.DeepCopyInto(&out.CertificateObjectStatus)
+	if in.PreviewURLs != nil {
+		in, out := &in.PreviewURLs, &out.PreviewURLs
+		*out = make([]PreviewURL, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostnamesStatus != nil {
+		in, out := &in.HostnamesStatus, &out.HostnamesStatus
+		*out = make([]HostnameStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteStatus.
@@ -668,6 +1202,21 @@ func (in *RouteStatus) DeepCopy() *RouteStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingModifiers) DeepCopyInto(out *ScalingModifiers) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingModifiers.
+func (in *ScalingModifiers) DeepCopy() *ScalingModifiers {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingModifiers)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StateStatus) DeepCopyInto(out *StateStatus) {
 	*out = *in
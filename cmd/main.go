@@ -20,6 +20,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -27,10 +28,12 @@ import (
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 
 	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
+	dnsv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	nfspvcv1alpha1 "github.com/dana-team/nfspvc-operator/api/v1alpha1"
 	"github.com/go-logr/zapr"
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 	routev1 "github.com/openshift/api/route/v1"
 	"go.elastic.co/ecszap"
@@ -45,16 +48,27 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	runtimezap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	cappcontroller "github.com/dana-team/container-app-operator/internal/kinds/capp/controllers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/sweeper"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	cappconfigcontroller "github.com/dana-team/container-app-operator/internal/kinds/cappconfig/controllers"
 	crcontroller "github.com/dana-team/container-app-operator/internal/kinds/capprevision/controllers"
+	"github.com/dana-team/container-app-operator/internal/metrics"
 	webhooks "github.com/dana-team/container-app-operator/internal/webhook/rcs/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
+const (
+	operatorNamespaceEnv = "OPERATOR_NAMESPACE"
+	podNamespaceEnv      = "POD_NAMESPACE"
+	cappConfigNameEnv    = "CAPP_CONFIG_NAME"
+)
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -69,10 +83,20 @@ func init() {
 	utilruntime.Must(nfspvcv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(cmapi.AddToScheme(scheme))
 	utilruntime.Must(dnsrecordv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(dnsv1beta1.SchemeBuilder.AddToScheme(scheme))
+	utilruntime.Must(kedav1alpha1.AddToScheme(scheme))
 
 	// +kubebuilder:scaffold:scheme
 }
 
+// getEnv returns the value of the environment variable with the given key, or the fallback if it is not set.
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
 func initOpenshiftSchemes() {
 	utilruntime.Must(routev1.Install(scheme))
 }
@@ -91,6 +115,11 @@ func main() {
 	var ecsLogging bool
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var orphanSweepInterval time.Duration
+	var orphanGracePeriod time.Duration
+	var orphanSweepDryRun bool
+	var operatorNamespace string
+	var cappConfigName string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -102,13 +131,29 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&orphanSweepInterval, "orphan-sweep-interval", 10*time.Minute,
+		"The interval between sweeps for resources whose parent Capp no longer exists. Set to 0 to disable the sweeps.")
+	flag.DurationVar(&orphanGracePeriod, "orphan-grace-period", time.Hour,
+		"How long a resource whose parent Capp no longer exists is kept before it is deleted.")
+	flag.BoolVar(&orphanSweepDryRun, "orphan-sweep-dry-run", false,
+		"If set, resources whose parent Capp no longer exists are only reported and never deleted.")
+	flag.StringVar(&operatorNamespace, "operator-namespace",
+		getEnv(operatorNamespaceEnv, getEnv(podNamespaceEnv, utils.DefaultCappNS)),
+		"The namespace of the operator, in which the CappConfigs are looked up. "+
+			"Defaults to the "+operatorNamespaceEnv+" or "+podNamespaceEnv+" environment variables.")
+	flag.StringVar(&cappConfigName, "capp-config-name", getEnv(cappConfigNameEnv, utils.DefaultCappConfigName),
+		"The name of the cluster CappConfig. Defaults to the "+cappConfigNameEnv+" environment variable.")
 	flag.Parse()
 
+	utils.CappNS = operatorNamespace
+	utils.CappConfigName = cappConfigName
+
 	if ecsLogging {
 		initEcsLogger()
 	} else {
 		ctrl.SetLogger(runtimezap.New())
 	}
+	setupLog.Info("using CappConfig", "namespace", utils.CappNS, "name", utils.CappConfigName)
 	metricsServerOptions := metricsserver.Options{
 		BindAddress:   metricsAddr,
 		SecureServing: secureMetrics,
@@ -161,10 +206,11 @@ func main() {
 	}
 
 	if err = (&cappcontroller.CappReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		OnOpenshift:   onOpenshift,
-		EventRecorder: mgr.GetEventRecorderFor("container-app-controller"),
+		Client:          mgr.GetClient(),
+		Scheme:          mgr.GetScheme(),
+		OnOpenshift:     onOpenshift,
+		EventRecorder:   mgr.GetEventRecorderFor("container-app-controller"),
+		MetricsProvider: rollout.NewPrometheusProvider(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Capp")
 		os.Exit(1)
@@ -179,6 +225,33 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&cappconfigcontroller.CappConfigReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CappConfig")
+		os.Exit(1)
+	}
+
+	if err := ctrlmetrics.Registry.Register(metrics.NewStateCollector(mgr.GetClient(), ctrl.Log.WithName("metrics"))); err != nil {
+		setupLog.Error(err, "unable to register metrics collector")
+		os.Exit(1)
+	}
+
+	if orphanSweepInterval > 0 {
+		if err := mgr.Add(&sweeper.OrphanSweeper{
+			Client:        mgr.GetClient(),
+			Log:           ctrl.Log.WithName("sweeper"),
+			EventRecorder: mgr.GetEventRecorderFor("capp-orphan-sweeper"),
+			Interval:      orphanSweepInterval,
+			GracePeriod:   orphanGracePeriod,
+			DryRun:        orphanSweepDryRun,
+		}); err != nil {
+			setupLog.Error(err, "unable to add orphan sweeper")
+			os.Exit(1)
+		}
+	}
+
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		hookServer := mgr.GetWebhookServer()
@@ -192,6 +265,11 @@ func main() {
 			Client:  mgr.GetClient(),
 			Decoder: decoder,
 		}})
+
+		hookServer.Register("/validate-cappconfig", &webhook.Admission{Handler: &webhooks.CappConfigValidator{
+			Client:  mgr.GetClient(),
+			Decoder: decoder,
+		}})
 	}
 	// +kubebuilder:scaffold:builder
 
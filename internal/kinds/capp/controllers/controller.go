@@ -2,7 +2,11 @@ package controllers
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
@@ -11,12 +15,18 @@ import (
 
 	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
 
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
 	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -32,23 +42,27 @@ import (
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
+	"github.com/dana-team/container-app-operator/internal/metrics"
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const (
-	cappControllerName = "CappController"
-	RequeueTime        = 5 * time.Second
+	cappControllerName   = "CappController"
+	RequeueTime          = 5 * time.Second
+	eventResourceBlocked = "ResourceBlocked"
 )
 
 // CappReconciler reconciles a Capp object
 type CappReconciler struct {
 	Log logr.Logger
 	client.Client
-	Scheme        *runtime.Scheme
-	OnOpenshift   bool
-	EventRecorder record.EventRecorder
+	Scheme          *runtime.Scheme
+	OnOpenshift     bool
+	EventRecorder   record.EventRecorder
+	MetricsProvider rollout.MetricsProvider
 }
 
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capps,verbs=get;list;watch;create;update;patch;delete
@@ -62,6 +76,7 @@ type CappReconciler struct {
 // +kubebuilder:rbac:groups=logging.banzaicloud.io,resources=syslogngoutputs,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;update;create
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;create;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;update;create;patch
@@ -69,6 +84,8 @@ type CappReconciler struct {
 // +kubebuilder:rbac:groups="nfspvc.dana.io",resources=nfspvcs,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups="record.dns-v2.m.crossplane.io",resources=cnamerecords,verbs=get;list;watch;update;create;delete
 // +kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;update;create;delete
+// +kubebuilder:rbac:groups=keda.sh,resources=triggerauthentications,verbs=get;list;watch;update;create;delete
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *CappReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -105,6 +122,26 @@ func (r *CappReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.findCappFromEvent),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
+		Watches(
+			&kedav1alpha1.ScaledObject{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappFromHostname),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&kedav1alpha1.TriggerAuthentication{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappFromHostname),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&cappv1alpha1.CappConfig{},
+			enqueueCappsRateLimited(r.findCappsFromConfig),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&corev1.Namespace{},
+			enqueueCappsRateLimited(r.findCappsFromNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
 		Complete(r)
 }
 
@@ -130,6 +167,90 @@ func (r *CappReconciler) findCappFromHostname(ctx context.Context, object client
 	return []reconcile.Request{request}
 }
 
+// enqueueCappsRateLimited returns an event handler which enqueues the Capps found for the changed object.
+// The Capps are added using the rate limiter of the queue, so that a change which affects many Capps,
+// such as a change to the CappConfig, does not reconcile all of them at once.
+func enqueueCappsRateLimited(findCapps handler.MapFunc) handler.EventHandler {
+	enqueue := func(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request], objects ...client.Object) {
+		for _, object := range objects {
+			for _, request := range findCapps(ctx, object) {
+				queue.AddRateLimited(request)
+			}
+		}
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, queue, e.Object)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, queue, e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, queue, e.Object)
+		},
+	}
+}
+
+// findCappsFromConfig maps a CappConfig to the Capps it applies to. The cluster CappConfig applies to all
+// the Capps, while an override applies to the Capps in the namespaces it selects.
+func (r *CappReconciler) findCappsFromConfig(ctx context.Context, object client.Object) []reconcile.Request {
+	cappConfig, ok := object.(*cappv1alpha1.CappConfig)
+	if !ok || cappConfig.Namespace != utils.CappNS {
+		return nil
+	}
+
+	if cappConfig.Name == utils.CappConfigName {
+		return r.findCappsInNamespaces(ctx, "")
+	}
+
+	if cappConfig.Spec.NamespaceSelector == nil {
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(cappConfig.Spec.NamespaceSelector)
+	if err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("invalid namespace selector of CappConfig %q", cappConfig.Name))
+		return nil
+	}
+
+	namespaces := corev1.NamespaceList{}
+	if err := r.List(ctx, &namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		log.FromContext(ctx).Error(err, fmt.Sprintf("failed to list namespaces selected by CappConfig %q", cappConfig.Name))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, namespace := range namespaces.Items {
+		requests = append(requests, r.findCappsInNamespaces(ctx, namespace.Name)...)
+	}
+
+	return requests
+}
+
+// findCappsFromNamespace maps a namespace to the Capps in it, since a change to its labels may change
+// the CappConfig overrides which apply to them.
+func (r *CappReconciler) findCappsFromNamespace(ctx context.Context, object client.Object) []reconcile.Request {
+	return r.findCappsInNamespaces(ctx, object.GetName())
+}
+
+// findCappsInNamespaces returns reconciliation requests of the Capps in the given namespace,
+// or of all the Capps if the namespace is empty.
+func (r *CappReconciler) findCappsInNamespaces(ctx context.Context, namespace string) []reconcile.Request {
+	cappList := cappv1alpha1.CappList{}
+	if err := r.List(ctx, &cappList, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list Capps to reconcile")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(cappList.Items))
+	for _, capp := range cappList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name}})
+	}
+
+	return requests
+}
+
 func (r *CappReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx).WithValues("CappName", req.Name, "CappNamespace", req.Namespace)
 	logger.Info("Starting Reconcile")
@@ -150,6 +271,7 @@ func (r *CappReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		rmanagers.SyslogNGFlow:   rmanagers.SyslogNGFlowManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
 		rmanagers.SyslogNGOutput: rmanagers.SyslogNGOutputManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
 		rmanagers.NfsPVC:         rmanagers.NFSPVCManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
+		rmanagers.KedaSource:     rmanagers.KedaSourceManager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder},
 	}
 
 	err, deleted := finalizer.HandleResourceDeletion(ctx, capp, r.Client, resourceManagers)
@@ -161,31 +283,119 @@ func (r *CappReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, nil
 	}
 
-	if err := finalizer.EnsureFinalizer(ctx, capp, r.Client); err != nil {
+	if err := finalizer.EnsureFinalizer(ctx, capp, r.Client, resourceManagers); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to ensure finalizer in Capp: %s", err.Error())
 	}
 
-	if err := r.SyncApplication(ctx, capp, resourceManagers, logger); err != nil {
+	rolloutManager := rollout.Manager{Ctx: ctx, Log: logger, K8sclient: r.Client, EventRecorder: r.EventRecorder, MetricsProvider: r.MetricsProvider}
+	requeueAfter, err := r.SyncApplication(ctx, capp, resourceManagers, rolloutManager, logger)
+	if err != nil {
 		if errors.IsConflict(err) {
 			logger.Info(fmt.Sprintf("Conflict detected, requeuing: %s", err.Error()))
 			return ctrl.Result{RequeueAfter: RequeueTime}, nil
 		}
 		return ctrl.Result{}, fmt.Errorf("failed to sync Capp: %s", err.Error())
 	}
-	return ctrl.Result{}, nil
+
+	if err := finalizer.ReleaseFinalizer(ctx, capp, r.Client, resourceManagers); err != nil {
+		if errors.IsConflict(err) {
+			logger.Info(fmt.Sprintf("Conflict detected, requeuing: %s", err.Error()))
+			return ctrl.Result{RequeueAfter: RequeueTime}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to release finalizer of Capp: %s", err.Error())
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // SyncApplication manages the lifecycle of Capp.
 // It ensures all manifests are applied according to the specification and synchronizes the status accordingly.
-func (r *CappReconciler) SyncApplication(ctx context.Context, capp cappv1alpha1.Capp, resourceManagers map[string]rmanagers.ResourceManager, logger logr.Logger) error {
-	for _, manager := range resourceManagers {
-		if err := manager.Manage(capp); err != nil {
-			return err
-		}
+// It returns how long to wait before the Capp should be reconciled again to progress its rollout.
+func (r *CappReconciler) SyncApplication(ctx context.Context, capp cappv1alpha1.Capp, resourceManagers map[string]rmanagers.ResourceManager, rolloutManager rollout.Manager, logger logr.Logger) (time.Duration, error) {
+	requeueAfter, err := rolloutManager.Sync(&capp)
+	if err != nil {
+		return 0, err
+	}
+
+	appliedConfigs, err := utils.GetAppliedCappConfigs(ctx, r.Client, capp.Namespace)
+	if err != nil {
+		logger.Error(err, "failed to get CappConfig")
+	} else {
+		capp.Status.CappConfigGenerations = buildCappConfigGenerations(appliedConfigs)
+	}
+
+	blockedResources, failures, manageErr := r.manageResources(capp, resourceManagers, logger)
+	capp.Status.BlockedResources = blockedResources
+
+	if err := status.SyncStatus(ctx, capp, logger, r.Client, r.OnOpenshift, resourceManagers, failures); err != nil {
+		return 0, stderrors.Join(manageErr, err)
+	}
+	return requeueAfter, manageErr
+}
+
+// buildCappConfigGenerations returns the names and generations of the given CappConfigs.
+func buildCappConfigGenerations(cappConfigs []cappv1alpha1.CappConfig) []cappv1alpha1.CappConfigGeneration {
+	generations := make([]cappv1alpha1.CappConfigGeneration, 0, len(cappConfigs))
+	for _, cappConfig := range cappConfigs {
+		generations = append(generations, cappv1alpha1.CappConfigGeneration{Name: cappConfig.Name, Generation: cappConfig.Generation})
+	}
+
+	return generations
+}
+
+// manageResources runs the resource managers in the order of their dependencies. A manager whose dependencies
+// are not ready yet is skipped, and returned as a blocked resource; an event is only emitted when it was not
+// already blocked on the same dependencies in the previous reconciliation. It is retried once its dependencies
+// change, since their resources are watched by the controller. A failing manager does not stop the others;
+// its error is returned in the failures, keyed by the manager name, and in the combined error.
+func (r *CappReconciler) manageResources(capp cappv1alpha1.Capp, resourceManagers map[string]rmanagers.ResourceManager, logger logr.Logger) ([]cappv1alpha1.BlockedResource, map[string]error, error) {
+	order, err := rmanagers.SortManagers(slices.Collect(maps.Keys(resourceManagers)), rmanagers.Dependencies)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := status.SyncStatus(ctx, capp, logger, r.Client, r.OnOpenshift, resourceManagers); err != nil {
-		return err
+	var blockedResources []cappv1alpha1.BlockedResource
+	var errs []error
+	failures := map[string]error{}
+	ready := map[string]bool{}
+	for _, name := range order {
+		manager := resourceManagers[name]
+
+		if manager.IsRequired(capp) {
+			var waitingFor []string
+			for _, dependency := range rmanagers.Dependencies[name] {
+				if _, ok := resourceManagers[dependency]; ok && !ready[dependency] {
+					waitingFor = append(waitingFor, dependency)
+				}
+			}
+
+			if len(waitingFor) > 0 {
+				logger.Info(fmt.Sprintf("Skipping %q until %v are ready", name, waitingFor))
+				blockedResource := cappv1alpha1.BlockedResource{Name: name, WaitingFor: waitingFor}
+				if !slices.ContainsFunc(capp.Status.BlockedResources, func(previous cappv1alpha1.BlockedResource) bool {
+					return equality.Semantic.DeepEqual(previous, blockedResource)
+				}) {
+					r.EventRecorder.Event(&capp, corev1.EventTypeNormal, eventResourceBlocked,
+						fmt.Sprintf("Waiting for %s to be ready before reconciling %s", strings.Join(waitingFor, ", "), name))
+				}
+				blockedResources = append(blockedResources, blockedResource)
+				continue
+			}
+		}
+
+		start := time.Now()
+		err := manager.Manage(capp)
+		metrics.ResourceManagerDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+		if err == nil {
+			ready[name], err = rmanagers.IsReady(manager, capp)
+		}
+
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("failed to manage %q", name))
+			metrics.ResourceManagerErrors.WithLabelValues(name).Inc()
+			failures[name] = err
+			errs = append(errs, fmt.Errorf("failed to manage %s: %w", name, err))
+		}
 	}
-	return nil
+
+	return blockedResources, failures, stderrors.Join(errs...)
 }
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fakeManager is a resource manager which is always required and whose readiness is fixed.
+type fakeManager struct {
+	ready bool
+}
+
+func (f fakeManager) Manage(cappv1alpha1.Capp) error          { return nil }
+func (f fakeManager) CleanUp(cappv1alpha1.Capp) error         { return nil }
+func (f fakeManager) IsRequired(cappv1alpha1.Capp) bool       { return true }
+func (f fakeManager) IsReady(cappv1alpha1.Capp) (bool, error) { return f.ready, nil }
+
+func TestManageResourcesBlockedEvents(t *testing.T) {
+	resourceManagers := map[string]rmanagers.ResourceManager{
+		rmanagers.KnativeServing: fakeManager{ready: true},
+		rmanagers.Certificate:    fakeManager{ready: false},
+		rmanagers.DomainMapping:  fakeManager{ready: true},
+	}
+	blockedOnCertificate := []cappv1alpha1.BlockedResource{{Name: rmanagers.DomainMapping, WaitingFor: []string{rmanagers.Certificate}}}
+
+	tests := []struct {
+		name             string
+		previousBlocked  []cappv1alpha1.BlockedResource
+		expectedEventLen int
+	}{
+		{
+			name:             "Event when a resource becomes blocked",
+			expectedEventLen: 1,
+		},
+		{
+			name:             "No event when a resource is still blocked on the same dependencies",
+			previousBlocked:  blockedOnCertificate,
+			expectedEventLen: 0,
+		},
+		{
+			name:             "Event when a resource is blocked on other dependencies",
+			previousBlocked:  []cappv1alpha1.BlockedResource{{Name: rmanagers.DomainMapping, WaitingFor: []string{rmanagers.DNSRecord}}},
+			expectedEventLen: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventRecorder := record.NewFakeRecorder(10)
+			r := &CappReconciler{EventRecorder: eventRecorder}
+
+			capp := cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"}}
+			capp.Status.BlockedResources = tt.previousBlocked
+
+			blockedResources, failures, err := r.manageResources(capp, resourceManagers, logr.Discard())
+			assert.NoError(t, err)
+			assert.Empty(t, failures)
+			assert.Equal(t, blockedOnCertificate, blockedResources)
+			assert.Len(t, eventRecorder.Events, tt.expectedEventLen)
+		})
+	}
+}
+
+// fakeQueue records the requests added to it, and whether they were added through the rate limiter.
+type fakeQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	added            []reconcile.Request
+	addedRateLimited []reconcile.Request
+}
+
+func (q *fakeQueue) Add(request reconcile.Request) {
+	q.added = append(q.added, request)
+}
+
+func (q *fakeQueue) AddRateLimited(request reconcile.Request) {
+	q.addedRateLimited = append(q.addedRateLimited, request)
+}
+
+func newConfigTestReconciler(t *testing.T) *CappReconciler {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+
+	objects := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "b"}}},
+		&cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "capp-a", Namespace: "team-a"}},
+		&cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "capp-b", Namespace: "team-b"}},
+	}
+
+	return &CappReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+}
+
+func TestFindCappsFromConfig(t *testing.T) {
+	r := newConfigTestReconciler(t)
+	cappA := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "capp-a"}}
+	cappB := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-b", Name: "capp-b"}}
+
+	tests := []struct {
+		name       string
+		cappConfig *cappv1alpha1.CappConfig
+		expected   []reconcile.Request
+	}{
+		{
+			name:       "Cluster CappConfig applies to every Capp",
+			cappConfig: &cappv1alpha1.CappConfig{ObjectMeta: metav1.ObjectMeta{Name: utils.CappConfigName, Namespace: utils.CappNS}},
+			expected:   []reconcile.Request{cappA, cappB},
+		},
+		{
+			name: "Override applies to the Capps in the selected namespaces",
+			cappConfig: &cappv1alpha1.CappConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a-override", Namespace: utils.CappNS},
+				Spec: cappv1alpha1.CappConfigSpec{NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"team": "a"},
+				}},
+			},
+			expected: []reconcile.Request{cappA},
+		},
+		{
+			name:       "Override without a namespace selector applies to no Capp",
+			cappConfig: &cappv1alpha1.CappConfig{ObjectMeta: metav1.ObjectMeta{Name: "no-selector", Namespace: utils.CappNS}},
+		},
+		{
+			name:       "CappConfig outside the operator namespace applies to no Capp",
+			cappConfig: &cappv1alpha1.CappConfig{ObjectMeta: metav1.ObjectMeta{Name: utils.CappConfigName, Namespace: "team-a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.expected, r.findCappsFromConfig(context.Background(), tt.cappConfig))
+		})
+	}
+}
+
+func TestEnqueueCappsRateLimited(t *testing.T) {
+	r := newConfigTestReconciler(t)
+	eventHandler := enqueueCappsRateLimited(r.findCappsFromConfig)
+	cappConfig := &cappv1alpha1.CappConfig{ObjectMeta: metav1.ObjectMeta{Name: utils.CappConfigName, Namespace: utils.CappNS}}
+	unrelatedConfig := &cappv1alpha1.CappConfig{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "team-a"}}
+
+	queue := &fakeQueue{}
+	eventHandler.Update(context.Background(), event.UpdateEvent{ObjectOld: cappConfig, ObjectNew: cappConfig}, queue)
+	assert.Empty(t, queue.added, "Expected the Capps not to be enqueued without the rate limiter")
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "capp-a"}},
+		{NamespacedName: types.NamespacedName{Namespace: "team-b", Name: "capp-b"}},
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "capp-a"}},
+		{NamespacedName: types.NamespacedName{Namespace: "team-b", Name: "capp-b"}},
+	}, queue.addedRateLimited)
+
+	queue = &fakeQueue{}
+	eventHandler.Create(context.Background(), event.CreateEvent{Object: unrelatedConfig}, queue)
+	assert.Empty(t, queue.added)
+	assert.Empty(t, queue.addedRateLimited, "Expected an unrelated CappConfig to enqueue no Capp")
+}
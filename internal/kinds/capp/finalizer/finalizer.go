@@ -2,21 +2,29 @@ package finalizer
 
 import (
 	"context"
+	"maps"
+	"slices"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const CappCleanupFinalizer = "dana.io/capp-cleanup"
 
-// HandleResourceDeletion manages the Capp deletion.
+// HandleResourceDeletion manages the Capp deletion. When the Capp is deleted with the orphan propagation policy,
+// its resources are left in place and only the finalizer is removed.
 func HandleResourceDeletion(ctx context.Context, capp cappv1alpha1.Capp, r client.Client, resourceManagers map[string]rmanagers.ResourceManager) (error, bool) {
 	if capp.DeletionTimestamp != nil {
 		if controllerutil.ContainsFinalizer(&capp, CappCleanupFinalizer) {
-			if err := finalizeCapp(capp, resourceManagers); err != nil {
-				return err, false
+			if !controllerutil.ContainsFinalizer(&capp, metav1.FinalizerOrphanDependents) {
+				if err := finalizeCapp(capp, resourceManagers); err != nil {
+					return err, false
+				}
 			}
 			return RemoveFinalizer(ctx, capp, r), true
 		}
@@ -33,23 +41,102 @@ func RemoveFinalizer(ctx context.Context, capp cappv1alpha1.Capp, r client.Clien
 	return nil
 }
 
-// finalizeCapp runs the cleanup of all the resource managers.
+// finalizeCapp runs the cleanup of all the resource managers, cleaning up dependents before their dependencies.
 func finalizeCapp(capp cappv1alpha1.Capp, resourceManagers map[string]rmanagers.ResourceManager) error {
-	for _, manager := range resourceManagers {
-		if err := manager.CleanUp(capp); err != nil {
+	order, err := rmanagers.SortManagers(slices.Collect(maps.Keys(resourceManagers)), rmanagers.Dependencies)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range slices.Backward(order) {
+		if err := resourceManagers[name].CleanUp(capp); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// EnsureFinalizer ensures the service has the finalizer.
-func EnsureFinalizer(ctx context.Context, service cappv1alpha1.Capp, r client.Client) error {
-	if !controllerutil.ContainsFinalizer(&service, CappCleanupFinalizer) {
-		controllerutil.AddFinalizer(&service, CappCleanupFinalizer)
-		if err := r.Update(ctx, &service); err != nil {
-			return err
+// IsFinalizerRequired returns whether the Capp has resources which are not garbage collected with it,
+// and therefore must be cleaned up by the finalizer. Resources in the namespace of the Capp are owned by it.
+func IsFinalizerRequired(capp cappv1alpha1.Capp, resourceManagers map[string]rmanagers.ResourceManager) (bool, error) {
+	for _, manager := range resourceManagers {
+		required, err := rmanagers.RequiresFinalization(manager, capp)
+		if err != nil || required {
+			return required, err
 		}
 	}
-	return nil
+	return false, nil
+}
+
+// EnsureFinalizer ensures the service has the finalizer if it has resources which must be cleaned up by it.
+func EnsureFinalizer(ctx context.Context, service cappv1alpha1.Capp, r client.Client, resourceManagers map[string]rmanagers.ResourceManager) error {
+	if controllerutil.ContainsFinalizer(&service, CappCleanupFinalizer) {
+		return nil
+	}
+
+	required, err := IsFinalizerRequired(service, resourceManagers)
+	if err != nil || !required {
+		return err
+	}
+
+	controllerutil.AddFinalizer(&service, CappCleanupFinalizer)
+	return r.Update(ctx, &service)
+}
+
+// ReleaseFinalizer removes the finalizer from the Capp once it no longer has resources which must be cleaned up
+// by it. It is called after the resources of the Capp are managed, so that they are already owned by the Capp
+// when the finalizer is removed. The finalizer is kept while resources of the Capp are blocked, or while any of
+// its resources, such as a previous DomainMapping kept during a hostname switch, is not owned by it yet.
+// The latest version of the Capp is fetched since its status was just updated.
+func ReleaseFinalizer(ctx context.Context, capp cappv1alpha1.Capp, r client.Client, resourceManagers map[string]rmanagers.ResourceManager) error {
+	if !controllerutil.ContainsFinalizer(&capp, CappCleanupFinalizer) {
+		return nil
+	}
+
+	required, err := IsFinalizerRequired(capp, resourceManagers)
+	if err != nil || required {
+		return err
+	}
+
+	latestCapp := cappv1alpha1.Capp{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(&capp), &latestCapp); err != nil {
+		return err
+	}
+
+	if len(latestCapp.Status.BlockedResources) > 0 {
+		return nil
+	}
+
+	owned, err := areResourcesOwned(ctx, latestCapp, r)
+	if err != nil || !owned {
+		return err
+	}
+	return RemoveFinalizer(ctx, latestCapp, r)
+}
+
+// areResourcesOwned returns whether every resource labeled with the name of the Capp in its namespace
+// has the Capp as its controller, so that it is garbage collected with the Capp.
+func areResourcesOwned(ctx context.Context, capp cappv1alpha1.Capp, r client.Client) (bool, error) {
+	for _, list := range rmanagers.ChildLists() {
+		if err := r.List(ctx, list, client.InNamespace(capp.Namespace), client.MatchingLabels{utils.CappResourceKey: capp.Name}); err != nil {
+			return false, err
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return false, err
+		}
+
+		for _, item := range items {
+			object, err := meta.Accessor(item)
+			if err != nil {
+				return false, err
+			}
+			if !metav1.IsControlledBy(object, &capp) {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
 }
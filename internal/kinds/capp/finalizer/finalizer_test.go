@@ -2,9 +2,18 @@ package finalizer
 
 import (
 	"context"
+	"slices"
 	"testing"
 
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	nfspvcv1alpha1 "github.com/dana-team/nfspvc-operator/api/v1alpha1"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
+	"github.com/go-logr/logr"
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -25,6 +34,11 @@ func newScheme() *runtime.Scheme {
 	_ = knativev1beta1.AddToScheme(s)
 	_ = knativev1.AddToScheme(s)
 	_ = routev1.Install(s)
+	_ = cmapi.AddToScheme(s)
+	_ = dnsrecordv1alpha1.AddToScheme(s)
+	_ = nfspvcv1alpha1.AddToScheme(s)
+	_ = kedav1alpha1.AddToScheme(s)
+	_ = loggingv1beta1.AddToScheme(s)
 	_ = scheme.AddToScheme(s)
 	return s
 }
@@ -34,11 +48,18 @@ func newFakeClient() client.Client {
 	return fake.NewClientBuilder().WithScheme(scheme).Build()
 }
 
+func newResourceManagers(ctx context.Context, k8sClient client.Client) map[string]rmanagers.ResourceManager {
+	return map[string]rmanagers.ResourceManager{
+		rmanagers.DomainMapping: rmanagers.KnativeDomainMappingManager{Ctx: ctx, K8sclient: k8sClient, Log: logr.Discard()},
+	}
+}
+
 func TestEnsureFinalizer(t *testing.T) {
 	ctx := context.Background()
 	capp := &cappv1alpha1.Capp{
 		Spec: cappv1alpha1.CappSpec{
 			RouteSpec: cappv1alpha1.RouteSpec{
+				Hostname:   "test.dev",
 				TlsEnabled: true,
 			},
 		},
@@ -50,12 +71,132 @@ func TestEnsureFinalizer(t *testing.T) {
 	fakeClient := newFakeClient()
 	assert.NoError(t, fakeClient.Create(ctx, capp), "Expected no error when creating capp")
 	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-capp", Namespace: "test-ns"}, capp))
-	assert.NoError(t, EnsureFinalizer(ctx, *capp, fakeClient))
+	assert.NoError(t, EnsureFinalizer(ctx, *capp, fakeClient, newResourceManagers(ctx, fakeClient)))
 	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-capp", Namespace: "test-ns"}, capp))
 	assert.Contains(t, capp.Finalizers, CappCleanupFinalizer)
 
 	// Check if there is no error after the finalizer exists.
-	assert.NoError(t, EnsureFinalizer(ctx, *capp, fakeClient))
+	assert.NoError(t, EnsureFinalizer(ctx, *capp, fakeClient, newResourceManagers(ctx, fakeClient)))
+}
+
+func TestEnsureFinalizerNotRequired(t *testing.T) {
+	ctx := context.Background()
+	capp := &cappv1alpha1.Capp{
+		Spec: cappv1alpha1.CappSpec{
+			RouteSpec: cappv1alpha1.RouteSpec{
+				Hostname: "test.dev",
+			},
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-capp",
+			Namespace: "test-ns",
+		},
+	}
+	fakeClient := newFakeClient()
+	assert.NoError(t, fakeClient.Create(ctx, capp))
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-capp", Namespace: "test-ns"}, capp))
+	assert.NoError(t, EnsureFinalizer(ctx, *capp, fakeClient, newResourceManagers(ctx, fakeClient)))
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-capp", Namespace: "test-ns"}, capp))
+	assert.NotContains(t, capp.Finalizers, CappCleanupFinalizer, "Expected no finalizer for a Capp whose resources are garbage collected")
+}
+
+func TestReleaseFinalizer(t *testing.T) {
+	ctx := context.Background()
+	capp := &cappv1alpha1.Capp{
+		Spec: cappv1alpha1.CappSpec{
+			RouteSpec: cappv1alpha1.RouteSpec{
+				Hostname:   "test.dev",
+				TlsEnabled: true,
+			},
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-capp",
+			Namespace:  "test-ns",
+			Finalizers: []string{CappCleanupFinalizer},
+		},
+	}
+	fakeClient := newFakeClient()
+	assert.NoError(t, fakeClient.Create(ctx, capp))
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-capp", Namespace: "test-ns"}, capp))
+	assert.NoError(t, ReleaseFinalizer(ctx, *capp, fakeClient, newResourceManagers(ctx, fakeClient)))
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-capp", Namespace: "test-ns"}, capp))
+	assert.Contains(t, capp.Finalizers, CappCleanupFinalizer, "Expected the finalizer to be kept while TLS secrets exist")
+
+	capp.Spec.RouteSpec.TlsEnabled = false
+	assert.NoError(t, fakeClient.Update(ctx, capp))
+	assert.NoError(t, ReleaseFinalizer(ctx, *capp, fakeClient, newResourceManagers(ctx, fakeClient)))
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-capp", Namespace: "test-ns"}, capp))
+	assert.NotContains(t, capp.Finalizers, CappCleanupFinalizer)
+}
+
+func TestReleaseFinalizerKeptForUnownedResources(t *testing.T) {
+	ctx := context.Background()
+	newCapp := func() *cappv1alpha1.Capp {
+		return &cappv1alpha1.Capp{
+			Spec: cappv1alpha1.CappSpec{RouteSpec: cappv1alpha1.RouteSpec{Hostname: "test.dev"}},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "test-capp",
+				Namespace:  "test-ns",
+				UID:        "test-uid",
+				Finalizers: []string{CappCleanupFinalizer},
+			},
+		}
+	}
+	newDNSRecord := func(ownerReferences []metav1.OwnerReference) *dnsrecordv1alpha1.CNAMERecord {
+		return &dnsrecordv1alpha1.CNAMERecord{ObjectMeta: metav1.ObjectMeta{
+			Name:            "old.test.dev",
+			Namespace:       "test-ns",
+			Labels:          map[string]string{utils.CappResourceKey: "test-capp"},
+			OwnerReferences: ownerReferences,
+		}}
+	}
+	ownedBy := func(capp *cappv1alpha1.Capp) []metav1.OwnerReference {
+		return []metav1.OwnerReference{*metav1.NewControllerRef(capp, cappv1alpha1.GroupVersion.WithKind("Capp"))}
+	}
+
+	tests := []struct {
+		name             string
+		blocked          []cappv1alpha1.BlockedResource
+		withDNSRecord    bool
+		dnsRecordOwned   bool
+		expectedReleased bool
+	}{
+		{
+			name:             "Released when every resource is owned",
+			withDNSRecord:    true,
+			dnsRecordOwned:   true,
+			expectedReleased: true,
+		},
+		{
+			name:          "Kept while a resource is not owned",
+			withDNSRecord: true,
+		},
+		{
+			name:    "Kept while a resource is blocked",
+			blocked: []cappv1alpha1.BlockedResource{{Name: rmanagers.DomainMapping, WaitingFor: []string{rmanagers.DNSRecord}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capp := newCapp()
+			capp.Status.BlockedResources = tt.blocked
+			objects := []client.Object{capp}
+			if tt.withDNSRecord {
+				var ownerReferences []metav1.OwnerReference
+				if tt.dnsRecordOwned {
+					ownerReferences = ownedBy(capp)
+				}
+				objects = append(objects, newDNSRecord(ownerReferences))
+			}
+
+			fakeClient := fake.NewClientBuilder().WithScheme(newScheme()).WithObjects(objects...).Build()
+			assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(capp), capp))
+			assert.NoError(t, ReleaseFinalizer(ctx, *capp, fakeClient, newResourceManagers(ctx, fakeClient)))
+			assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(capp), capp))
+			assert.Equal(t, !tt.expectedReleased, slices.Contains(capp.Finalizers, CappCleanupFinalizer))
+		})
+	}
 }
 
 func TestRemoveFinalizer(t *testing.T) {
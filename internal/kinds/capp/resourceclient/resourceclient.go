@@ -2,38 +2,140 @@ package resourceclient
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"maps"
 
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
+// FieldManager is the field manager the child resources of a Capp are applied with.
+const FieldManager = "container-app-operator"
+
+// AppliedHashAnnotationKey is the annotation holding the hash of the configuration a resource was last applied with.
+var AppliedHashAnnotationKey = cappv1alpha1.GroupVersion.Group + "/applied-hash"
+
 type ResourceManagerClient struct {
 	Ctx       context.Context
 	K8sclient client.Client
 	Log       logr.Logger
 }
 
-// CreateResource creates a resource.
-func (r ResourceManagerClient) CreateResource(resource client.Object) error {
-	if err := r.K8sclient.Create(r.Ctx, resource); err != nil {
-		return fmt.Errorf("failed to create resource %s %s: %w", resource.GetObjectKind().GroupVersionKind().Kind, resource.GetName(), err)
+// ApplyResource applies a resource using server-side apply, creating it if it does not exist. The apply is
+// first dry-run, and the resource is only applied if its fields would change. It returns whether the resource
+// drifted, i.e. fields set by the operator were changed by someone else since it was last applied.
+func (r ResourceManagerClient) ApplyResource(resource client.Object) (bool, error) {
+	gvk, err := apiutil.GVKForObject(resource, r.K8sclient.Scheme())
+	if err != nil {
+		return false, err
 	}
+	resource.GetObjectKind().SetGroupVersionKind(gvk)
 
-	r.Log.Info(fmt.Sprintf("successfully created %s %s", resource.GetObjectKind().GroupVersionKind().Kind, resource.GetName()))
-	return nil
+	hash, err := hashResource(resource)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash %s %s: %w", gvk.Kind, resource.GetName(), err)
+	}
+
+	annotations := maps.Clone(resource.GetAnnotations())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AppliedHashAnnotationKey] = hash
+	resource.SetAnnotations(annotations)
+
+	existingObject, err := r.K8sclient.Scheme().New(gvk)
+	if err != nil {
+		return false, err
+	}
+	existing := existingObject.(client.Object)
+	if err := r.K8sclient.Get(r.Ctx, client.ObjectKeyFromObject(resource), existing); err != nil {
+		if errors.IsNotFound(err) {
+			return false, r.applyResource(resource)
+		}
+		return false, fmt.Errorf("failed to get %s %s: %w", gvk.Kind, resource.GetName(), err)
+	}
+
+	dryRun := resource.DeepCopyObject().(client.Object)
+	if err := r.K8sclient.Patch(r.Ctx, dryRun, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership, client.DryRunAll); err != nil {
+		return false, fmt.Errorf("failed to dry-run apply %s %s: %w", gvk.Kind, resource.GetName(), err)
+	}
+
+	upToDate, err := isUpToDate(existing, dryRun)
+	if err != nil {
+		return false, err
+	}
+
+	if upToDate {
+		return false, nil
+	}
+
+	drifted := existing.GetAnnotations()[AppliedHashAnnotationKey] == hash
+	return drifted, r.applyResource(resource)
 }
 
-// UpdateResource updates a resource.
-func (r ResourceManagerClient) UpdateResource(resource client.Object) error {
-	if err := r.K8sclient.Update(r.Ctx, resource); err != nil {
-		return fmt.Errorf("failed to update %s %s: %w", resource.GetObjectKind().GroupVersionKind().Kind, resource.GetName(), err)
+// applyResource applies a resource using server-side apply.
+func (r ResourceManagerClient) applyResource(resource client.Object) error {
+	if err := r.K8sclient.Patch(r.Ctx, resource, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply %s %s: %w", resource.GetObjectKind().GroupVersionKind().Kind, resource.GetName(), err)
 	}
 
-	r.Log.Info(fmt.Sprintf("successfully updated %s %s", resource.GetObjectKind().GroupVersionKind().Kind, resource.GetName()))
+	r.Log.Info(fmt.Sprintf("successfully applied %s %s", resource.GetObjectKind().GroupVersionKind().Kind, resource.GetName()))
 	return nil
 }
 
+// hashResource returns a hash of the configuration of the given resource.
+func hashResource(resource client.Object) (string, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isUpToDate returns whether the existing resource is equal to the result of applying the desired
+// configuration, ignoring the fields which are not part of the configuration of the resource.
+func isUpToDate(existing, applied client.Object) (bool, error) {
+	existingContent, err := comparableContent(existing)
+	if err != nil {
+		return false, err
+	}
+
+	appliedContent, err := comparableContent(applied)
+	if err != nil {
+		return false, err
+	}
+
+	return equality.Semantic.DeepEqual(existingContent, appliedContent), nil
+}
+
+// comparableContent returns the content of the resource without its type, status and server-managed metadata.
+func comparableContent(resource client.Object) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(content, "apiVersion")
+	delete(content, "kind")
+	delete(content, "status")
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "managedFields")
+		delete(metadata, "resourceVersion")
+	}
+
+	return content, nil
+}
+
 // DeleteResource deletes a resource.
 func (r ResourceManagerClient) DeleteResource(resource client.Object) error {
 	if err := r.K8sclient.Delete(r.Ctx, resource); err != nil {
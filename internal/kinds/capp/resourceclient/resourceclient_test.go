@@ -0,0 +1,67 @@
+package resourceclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestConfigMap(value string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-configmap", Namespace: "test-ns"},
+		Data:       map[string]string{"key": value},
+	}
+}
+
+func TestApplyResource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	resourceManager := ResourceManagerClient{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard()}
+
+	drifted, err := resourceManager.ApplyResource(newTestConfigMap("desired"))
+	assert.NoError(t, err)
+	assert.False(t, drifted, "Creating a resource is not a drift")
+
+	configMap := corev1.ConfigMap{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "test-configmap"}, &configMap))
+	assert.Equal(t, "desired", configMap.Data["key"])
+	assert.NotEmpty(t, configMap.Annotations[AppliedHashAnnotationKey])
+
+	drifted, err = resourceManager.ApplyResource(newTestConfigMap("changed"))
+	assert.NoError(t, err)
+	assert.False(t, drifted, "Changing the desired state is not a drift")
+
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "test-configmap"}, &configMap))
+	configMap.Data["key"] = "edited"
+	assert.NoError(t, k8sClient.Update(context.Background(), &configMap))
+
+	drifted, err = resourceManager.ApplyResource(newTestConfigMap("changed"))
+	assert.NoError(t, err)
+	assert.True(t, drifted, "Editing a managed field is a drift")
+
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "test-configmap"}, &configMap))
+	assert.Equal(t, "changed", configMap.Data["key"])
+}
+
+func TestIsUpToDate(t *testing.T) {
+	existing := newTestConfigMap("value")
+	existing.ResourceVersion = "42"
+	existing.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: FieldManager}}
+
+	upToDate, err := isUpToDate(existing, newTestConfigMap("value"))
+	assert.NoError(t, err)
+	assert.True(t, upToDate)
+
+	upToDate, err = isUpToDate(existing, newTestConfigMap("other"))
+	assert.NoError(t, err)
+	assert.False(t, upToDate)
+}
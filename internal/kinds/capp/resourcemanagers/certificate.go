@@ -4,12 +4,14 @@ import (
 	"context"
 
 	"fmt"
+	"slices"
 
 	certv1alpha1 "github.com/dana-team/cert-external-issuer/api/v1alpha1"
 
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -39,9 +41,9 @@ type CertificateManager struct {
 	EventRecorder record.EventRecorder
 }
 
-// prepareResource prepares a Certificate resource based on the provided Capp.
-func (c CertificateManager) prepareResource(capp cappv1alpha1.Capp) (cmapi.Certificate, error) {
-	dnsConfig, err := utils.GetDNSConfig(c.Ctx, c.K8sclient)
+// prepareResource prepares a Certificate resource for the given hostname of the provided Capp.
+func (c CertificateManager) prepareResource(capp cappv1alpha1.Capp, hostname string) (cmapi.Certificate, error) {
+	dnsConfig, err := utils.GetDNSConfig(c.Ctx, c.K8sclient, capp.Namespace)
 	if err != nil {
 		return cmapi.Certificate{}, err
 	}
@@ -56,14 +58,15 @@ func (c CertificateManager) prepareResource(capp cappv1alpha1.Capp) (cmapi.Certi
 		return cmapi.Certificate{}, err
 	}
 
-	resourceName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
+	resourceName := utils.GenerateResourceName(hostname, zone)
 	secretName := utils.GenerateSecretName(resourceName)
 
 	certificate := cmapi.Certificate{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      resourceName,
-			Namespace: capp.Namespace,
+			Name:            resourceName,
+			Namespace:       capp.Namespace,
+			OwnerReferences: ownerReferences(capp),
 			Labels: map[string]string{
 				utils.CappResourceKey:   capp.Name,
 				utils.ManagedByLabelKey: utils.CappKey,
@@ -121,7 +124,52 @@ func (c CertificateManager) CleanUp(capp cappv1alpha1.Capp) error {
 
 // IsRequired is responsible to determine if resource Certificate is required.
 func (c CertificateManager) IsRequired(capp cappv1alpha1.Capp) bool {
-	return capp.Spec.RouteSpec.TlsEnabled && utils.IsCustomHostnameSet(capp.Spec.RouteSpec.Hostname)
+	for _, routeHostname := range utils.GetRouteHostnames(capp.Spec.RouteSpec, rollout.GetTrafficTargets(capp)) {
+		if routeHostname.TlsEnabled {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsReady returns whether the Certificates of all the Capp hostnames and previews which have TLS enabled are issued.
+func (c CertificateManager) IsReady(capp cappv1alpha1.Capp) (bool, error) {
+	for _, routeHostname := range utils.GetRouteHostnames(capp.Spec.RouteSpec, rollout.GetTrafficTargets(capp)) {
+		if !routeHostname.TlsEnabled {
+			continue
+		}
+
+		certificateFromCapp, err := c.prepareResource(capp, routeHostname.Hostname)
+		if err != nil {
+			return false, fmt.Errorf("failed to prepare Certificate: %w", err)
+		}
+
+		certificate := cmapi.Certificate{}
+		if err := c.K8sclient.Get(c.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: certificateFromCapp.Name}, &certificate); err != nil {
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get Certificate %q: %w", certificateFromCapp.Name, err)
+		}
+
+		if !isCertificateReady(certificate) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// isCertificateReady returns whether the Certificate has a true Ready condition.
+func isCertificateReady(certificate cmapi.Certificate) bool {
+	for _, condition := range certificate.Status.Conditions {
+		if condition.Type == cmapi.CertificateConditionReady {
+			return condition.Status == cmmeta.ConditionTrue
+		}
+	}
+
+	return false
 }
 
 // Manage creates or updates a Certificate resource based on the provided Capp if it's required.
@@ -134,30 +182,38 @@ func (c CertificateManager) Manage(capp cappv1alpha1.Capp) error {
 	return c.CleanUp(capp)
 }
 
-// create creates a Certificate resource.
+// create creates or applies the Certificate resources of the Capp hostnames and previews which have TLS enabled.
 func (c CertificateManager) create(capp cappv1alpha1.Capp) error {
-	certificateFromCapp, err := c.prepareResource(capp)
-	if err != nil {
-		return fmt.Errorf("failed to prepare Certificate: %w", err)
-	}
-
-	certificate := cmapi.Certificate{}
 	resourceManager := rclient.ResourceManagerClient{Ctx: c.Ctx, K8sclient: c.K8sclient, Log: c.Log}
 
-	if err := c.K8sclient.Get(c.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: certificateFromCapp.Name}, &certificate); err != nil {
-		if errors.IsNotFound(err) {
+	var names []string
+	for _, routeHostname := range utils.GetRouteHostnames(capp.Spec.RouteSpec, rollout.GetTrafficTargets(capp)) {
+		if !routeHostname.TlsEnabled {
+			continue
+		}
+
+		certificateFromCapp, err := c.prepareResource(capp, routeHostname.Hostname)
+		if err != nil {
+			return fmt.Errorf("failed to prepare Certificate: %w", err)
+		}
+
+		certificate := cmapi.Certificate{}
+		if err := c.K8sclient.Get(c.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: certificateFromCapp.Name}, &certificate); err != nil {
+			if !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to get Certificate %q: %w", certificateFromCapp.Name, err)
+			}
+
 			if err := c.createCertificate(capp, certificateFromCapp, resourceManager); err != nil {
 				return err
 			}
-
-			return c.createCertificate(capp, certificateFromCapp, resourceManager)
-		} else {
-			return fmt.Errorf("failed to get Certificate %q: %w", certificateFromCapp.Name, err)
+		} else if err := applyResource(capp, &certificateFromCapp, resourceManager, c.EventRecorder); err != nil {
+			return err
 		}
+		names = append(names, certificateFromCapp.Name)
 	}
 
 	if capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil {
-		if err := c.handlePreviousCertificates(capp, resourceManager, certificateFromCapp.Name); err != nil {
+		if err := c.handlePreviousCertificates(capp, resourceManager, names); err != nil {
 			return fmt.Errorf("failed to handle previous Certificates: %w", err)
 		}
 	}
@@ -167,7 +223,7 @@ func (c CertificateManager) create(capp cappv1alpha1.Capp) error {
 
 // createCertificate creates a new Certificate and emits an event.
 func (c CertificateManager) createCertificate(capp cappv1alpha1.Capp, certificateFromCapp cmapi.Certificate, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(&certificateFromCapp); err != nil {
+	if _, err := resourceManager.ApplyResource(&certificateFromCapp); err != nil {
 		c.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappCertificateCreationFailed,
 			fmt.Sprintf("Failed to create Certificate %s", certificateFromCapp.Name))
 
@@ -180,14 +236,14 @@ func (c CertificateManager) createCertificate(capp cappv1alpha1.Capp, certificat
 	return nil
 }
 
-// handlePreviousCertificates takes care of removing unneeded Certificate objects. If the DNSRecord
-// which corresponds to the latest Certificate object is not yet available then return early
-// and do not delete the previous Certificates.
-func (c CertificateManager) handlePreviousCertificates(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, name string) error {
+// handlePreviousCertificates takes care of removing unneeded Certificate objects, keeping the ones with the
+// given names. If the DNSRecord which corresponds to the first of these Certificates is not yet available
+// then return early and do not delete the previous Certificates.
+func (c CertificateManager) handlePreviousCertificates(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, names []string) error {
 	var available bool
 	var err error
 
-	available, err = utils.IsDNSRecordAvailable(c.Ctx, c.K8sclient, name, capp.Namespace)
+	available, err = utils.IsDNSRecordAvailable(c.Ctx, c.K8sclient, names[0], capp.Namespace)
 	if err != nil {
 		return err
 	}
@@ -201,7 +257,7 @@ func (c CertificateManager) handlePreviousCertificates(capp cappv1alpha1.Capp, r
 		return err
 	}
 
-	return c.deletePreviousCertificates(certificates, resourceManager, capp.Spec.RouteSpec.Hostname)
+	return c.deletePreviousCertificates(certificates, resourceManager, names)
 }
 
 // getPreviousCertificates returns a list of all Certificate objects that are related to the given Capp.
@@ -220,10 +276,10 @@ func (c CertificateManager) getPreviousCertificates(capp cappv1alpha1.Capp) (cma
 	return certificates, nil
 }
 
-// deletePreviousCertificates deletes all previous Certificates associated with a Capp.
-func (c CertificateManager) deletePreviousCertificates(certificates cmapi.CertificateList, resourceManager rclient.ResourceManagerClient, hostname string) error {
+// deletePreviousCertificates deletes all previous Certificates associated with a Capp, except for the ones with the given names.
+func (c CertificateManager) deletePreviousCertificates(certificates cmapi.CertificateList, resourceManager rclient.ResourceManagerClient, names []string) error {
 	for _, certificate := range certificates.Items {
-		if certificate.Name != hostname {
+		if !slices.Contains(names, certificate.Name) {
 			cert := rclient.GetBareCertificate(certificate.Name, certificate.Namespace)
 			if err := resourceManager.DeleteResource(&cert); err != nil {
 				return err
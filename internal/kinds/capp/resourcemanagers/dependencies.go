@@ -0,0 +1,67 @@
+package resourcemanagers
+
+import (
+	"fmt"
+	"slices"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+)
+
+// Dependencies declares, for every resource manager, the resource managers whose resources
+// must be ready before its own resources are reconciled.
+var Dependencies = map[string][]string{
+	DomainMapping: {KnativeServing, DNSRecord, Certificate},
+	SyslogNGFlow:  {SyslogNGOutput},
+	KedaSource:    {KnativeServing},
+}
+
+// ReadinessChecker is implemented by resource managers whose resources take time to become usable
+// after they are created, such as a Certificate which needs to be issued.
+type ReadinessChecker interface {
+	IsReady(capp cappv1alpha1.Capp) (bool, error)
+}
+
+// IsReady returns whether the resources of the given manager are ready to be used by the resources which
+// depend on them. Resources which are not required, or whose manager does not implement ReadinessChecker,
+// are considered ready.
+func IsReady(manager ResourceManager, capp cappv1alpha1.Capp) (bool, error) {
+	if !manager.IsRequired(capp) {
+		return true, nil
+	}
+
+	checker, ok := manager.(ReadinessChecker)
+	if !ok {
+		return true, nil
+	}
+
+	return checker.IsReady(capp)
+}
+
+// SortManagers returns the names of the given resource managers ordered so that every manager comes after
+// the managers it depends on. Managers which do not depend on each other are ordered by name, so the order
+// is the same on every call. Dependencies on managers which are not in the given names are ignored.
+func SortManagers(names []string, dependencies map[string][]string) ([]string, error) {
+	remaining := slices.Clone(names)
+	slices.Sort(remaining)
+
+	sorted := make([]string, 0, len(names))
+	for len(remaining) > 0 {
+		index := slices.IndexFunc(remaining, func(name string) bool {
+			for _, dependency := range dependencies[name] {
+				if slices.Contains(remaining, dependency) {
+					return false
+				}
+			}
+			return true
+		})
+
+		if index == -1 {
+			return nil, fmt.Errorf("resource managers %v have cyclic dependencies", remaining)
+		}
+
+		sorted = append(sorted, remaining[index])
+		remaining = slices.Delete(remaining, index, index+1)
+	}
+
+	return sorted, nil
+}
@@ -0,0 +1,101 @@
+package resourcemanagers
+
+import (
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeManager struct {
+	required bool
+}
+
+func (f fakeManager) Manage(cappv1alpha1.Capp) error    { return nil }
+func (f fakeManager) CleanUp(cappv1alpha1.Capp) error   { return nil }
+func (f fakeManager) IsRequired(cappv1alpha1.Capp) bool { return f.required }
+
+type fakeReadinessManager struct {
+	fakeManager
+	ready bool
+}
+
+func (f fakeReadinessManager) IsReady(cappv1alpha1.Capp) (bool, error) { return f.ready, nil }
+
+func TestSortManagers(t *testing.T) {
+	tests := []struct {
+		name          string
+		names         []string
+		dependencies  map[string][]string
+		expected      []string
+		errorContains string
+	}{
+		{
+			name:         "Default dependencies",
+			names:        []string{KedaSource, DomainMapping, SyslogNGFlow, Certificate, NfsPVC, DNSRecord, SyslogNGOutput, KnativeServing},
+			dependencies: Dependencies,
+			expected:     []string{DNSRecord, Certificate, KnativeServing, DomainMapping, KedaSource, NfsPVC, SyslogNGOutput, SyslogNGFlow},
+		},
+		{
+			name:         "Missing dependencies are ignored",
+			names:        []string{DomainMapping, KnativeServing},
+			dependencies: Dependencies,
+			expected:     []string{KnativeServing, DomainMapping},
+		},
+		{
+			name:          "Cyclic dependencies",
+			names:         []string{"a", "b", "c"},
+			dependencies:  map[string][]string{"a": {"b"}, "b": {"a"}},
+			errorContains: "cyclic dependencies",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := SortManagers(tt.names, tt.dependencies)
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, sorted)
+		})
+	}
+}
+
+func TestIsReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		manager  ResourceManager
+		expected bool
+	}{
+		{
+			name:     "Not required",
+			manager:  fakeReadinessManager{fakeManager: fakeManager{required: false}, ready: false},
+			expected: true,
+		},
+		{
+			name:     "No readiness check",
+			manager:  fakeManager{required: true},
+			expected: true,
+		},
+		{
+			name:     "Not ready",
+			manager:  fakeReadinessManager{fakeManager: fakeManager{required: true}, ready: false},
+			expected: false,
+		},
+		{
+			name:     "Ready",
+			manager:  fakeReadinessManager{fakeManager: fakeManager{required: true}, ready: true},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, err := IsReady(tt.manager, cappv1alpha1.Capp{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ready)
+		})
+	}
+}
@@ -3,13 +3,14 @@ package resourcemanagers
 import (
 	"context"
 	"fmt"
-	"reflect"
+	"slices"
 
 	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -36,9 +37,9 @@ type DNSRecordManager struct {
 	EventRecorder record.EventRecorder
 }
 
-// prepareResource prepares a DNSRecord resource based on the provided Capp.
-func (r DNSRecordManager) prepareResource(capp cappv1alpha1.Capp) (dnsrecordv1alpha1.CNAMERecord, error) {
-	dnsConfig, err := utils.GetDNSConfig(r.Ctx, r.K8sclient)
+// prepareResource prepares a DNSRecord resource for the given hostname of the provided Capp.
+func (r DNSRecordManager) prepareResource(capp cappv1alpha1.Capp, hostname string) (dnsrecordv1alpha1.CNAMERecord, error) {
+	dnsConfig, err := utils.GetDNSConfig(r.Ctx, r.K8sclient, capp.Namespace)
 	if err != nil {
 		return dnsrecordv1alpha1.CNAMERecord{}, err
 	}
@@ -53,14 +54,15 @@ func (r DNSRecordManager) prepareResource(capp cappv1alpha1.Capp) (dnsrecordv1al
 		return dnsrecordv1alpha1.CNAMERecord{}, err
 	}
 
-	resourceName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
-	recordName := utils.GenerateRecordName(capp.Spec.RouteSpec.Hostname, zone)
+	resourceName := utils.GenerateResourceName(hostname, zone)
+	recordName := utils.GenerateRecordName(hostname, zone)
 
 	dnsRecord := dnsrecordv1alpha1.CNAMERecord{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      resourceName,
-			Namespace: capp.Namespace,
+			Name:            resourceName,
+			Namespace:       capp.Namespace,
+			OwnerReferences: ownerReferences(capp),
 			Labels: map[string]string{
 				utils.CappResourceKey:   capp.Name,
 				utils.CappNamespaceKey:  capp.Namespace,
@@ -111,6 +113,30 @@ func (r DNSRecordManager) IsRequired(capp cappv1alpha1.Capp) bool {
 	return capp.Spec.RouteSpec.Hostname != ""
 }
 
+// IsReady returns whether the DNSRecords of all the Capp hostnames and of its previews are available.
+func (r DNSRecordManager) IsReady(capp cappv1alpha1.Capp) (bool, error) {
+	for _, routeHostname := range utils.GetRouteHostnames(capp.Spec.RouteSpec, rollout.GetTrafficTargets(capp)) {
+		dnsRecordFromCapp, err := r.prepareResource(capp, routeHostname.Hostname)
+		if err != nil {
+			return false, fmt.Errorf("failed to prepare DNSRecord: %w", err)
+		}
+
+		available, err := utils.IsDNSRecordAvailable(r.Ctx, r.K8sclient, dnsRecordFromCapp.Name, capp.Namespace)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		if !available {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // Manage creates or updates a DNSRecord resource based on the provided Capp if it's required.
 // If it's not, then it cleans up the resource if it exists.
 func (r DNSRecordManager) Manage(capp cappv1alpha1.Capp) error {
@@ -121,38 +147,48 @@ func (r DNSRecordManager) Manage(capp cappv1alpha1.Capp) error {
 	return r.CleanUp(capp)
 }
 
-// createOrUpdate creates or updates a DomainMapping resource.
+// createOrUpdate creates or updates the DNSRecord resources of the Capp hostnames and of its previews.
 func (r DNSRecordManager) createOrUpdate(capp cappv1alpha1.Capp) error {
-	dnsRecordFromCapp, err := r.prepareResource(capp)
-	if err != nil {
-		return fmt.Errorf("failed to prepare DNSRecord: %w", err)
-	}
-
-	dnsRecord := dnsrecordv1alpha1.CNAMERecord{}
 	resourceManager := rclient.ResourceManagerClient{Ctx: r.Ctx, K8sclient: r.K8sclient, Log: r.Log}
 
-	if err := r.K8sclient.Get(r.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: dnsRecordFromCapp.Name}, &dnsRecord); err != nil {
-		if errors.IsNotFound(err) {
-			if err := r.createDNSRecord(capp, dnsRecordFromCapp, resourceManager); err != nil {
-				return err
-			}
-		} else {
-			return fmt.Errorf("failed to get DNSRecord %q: %w", dnsRecordFromCapp.Name, err)
+	var names []string
+	for _, routeHostname := range utils.GetRouteHostnames(capp.Spec.RouteSpec, rollout.GetTrafficTargets(capp)) {
+		dnsRecordFromCapp, err := r.prepareResource(capp, routeHostname.Hostname)
+		if err != nil {
+			return fmt.Errorf("failed to prepare DNSRecord: %w", err)
 		}
+
+		if err := r.createOrUpdateDNSRecord(capp, dnsRecordFromCapp, resourceManager); err != nil {
+			return err
+		}
+		names = append(names, dnsRecordFromCapp.Name)
 	}
 
 	if capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil {
-		if err := r.handlePreviousDNSRecords(capp, resourceManager, dnsRecordFromCapp.Name); err != nil {
-			return fmt.Errorf("failed to delete previous Certificates: %w", err)
+		if err := r.handlePreviousDNSRecords(capp, resourceManager, names); err != nil {
+			return fmt.Errorf("failed to delete previous DNSRecords: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// createOrUpdateDNSRecord creates the DNSRecord if it does not exist, or applies it otherwise.
+func (r DNSRecordManager) createOrUpdateDNSRecord(capp cappv1alpha1.Capp, dnsRecordFromCapp dnsrecordv1alpha1.CNAMERecord, resourceManager rclient.ResourceManagerClient) error {
+	dnsRecord := dnsrecordv1alpha1.CNAMERecord{}
+	if err := r.K8sclient.Get(r.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: dnsRecordFromCapp.Name}, &dnsRecord); err != nil {
+		if errors.IsNotFound(err) {
+			return r.createDNSRecord(capp, dnsRecordFromCapp, resourceManager)
 		}
+		return fmt.Errorf("failed to get DNSRecord %q: %w", dnsRecordFromCapp.Name, err)
 	}
 
-	return r.updateDNSRecord(dnsRecord, dnsRecordFromCapp, resourceManager)
+	return applyResource(capp, &dnsRecordFromCapp, resourceManager, r.EventRecorder)
 }
 
 // createDNSRecord creates a new DNSRecord and emits an event.
 func (r DNSRecordManager) createDNSRecord(capp cappv1alpha1.Capp, dnsRecordFromCapp dnsrecordv1alpha1.CNAMERecord, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(&dnsRecordFromCapp); err != nil {
+	if _, err := resourceManager.ApplyResource(&dnsRecordFromCapp); err != nil {
 		r.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappDNSRecordCreationFailed,
 			fmt.Sprintf("Failed to create DNSRecord %s", dnsRecordFromCapp.Name))
 
@@ -165,20 +201,10 @@ func (r DNSRecordManager) createDNSRecord(capp cappv1alpha1.Capp, dnsRecordFromC
 	return nil
 }
 
-// updateDNSRecord checks if an update to the DNSRecord is necessary and performs the update to match desired state.
-func (r DNSRecordManager) updateDNSRecord(dnsRecord, dnsRecordFromCapp dnsrecordv1alpha1.CNAMERecord, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(dnsRecord.Spec, dnsRecordFromCapp.Spec) {
-		dnsRecord.Spec = dnsRecordFromCapp.Spec
-		return resourceManager.UpdateResource(&dnsRecord)
-	}
-
-	return nil
-}
-
-// handlePreviousDNSRecords takes care of removing unneeded DNSRecord objects. If the new DNSRecord
-// is not yet available then return early and do not delete the previous Records.
-func (r DNSRecordManager) handlePreviousDNSRecords(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, name string) error {
-	available, err := utils.IsDNSRecordAvailable(r.Ctx, r.K8sclient, name, capp.Namespace)
+// handlePreviousDNSRecords takes care of removing unneeded DNSRecord objects, keeping the ones with the given names.
+// If the first of these DNSRecords is not yet available then return early and do not delete the previous Records.
+func (r DNSRecordManager) handlePreviousDNSRecords(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, names []string) error {
+	available, err := utils.IsDNSRecordAvailable(r.Ctx, r.K8sclient, names[0], capp.Namespace)
 	if err != nil {
 		return err
 	}
@@ -192,7 +218,7 @@ func (r DNSRecordManager) handlePreviousDNSRecords(capp cappv1alpha1.Capp, resou
 		return err
 	}
 
-	return r.deletePreviousDNSRecords(dnsRecords, resourceManager, capp.Spec.RouteSpec.Hostname)
+	return r.deletePreviousDNSRecords(dnsRecords, resourceManager, names)
 }
 
 // getPreviousDNSRecords returns a list of all DNSRecord objects that are related to the given Capp.
@@ -213,10 +239,10 @@ func (r DNSRecordManager) getPreviousDNSRecords(capp cappv1alpha1.Capp) (dnsreco
 	return dnsRecords, nil
 }
 
-// deletePreviousDNSRecords deletes all previous DNSRecords associated with a Capp.
-func (r DNSRecordManager) deletePreviousDNSRecords(dnsRecords dnsrecordv1alpha1.CNAMERecordList, resourceManager rclient.ResourceManagerClient, hostname string) error {
+// deletePreviousDNSRecords deletes all previous DNSRecords associated with a Capp, except for the ones with the given names.
+func (r DNSRecordManager) deletePreviousDNSRecords(dnsRecords dnsrecordv1alpha1.CNAMERecordList, resourceManager rclient.ResourceManagerClient, names []string) error {
 	for _, dnsRecord := range dnsRecords.Items {
-		if dnsRecord.Name != hostname {
+		if !slices.Contains(names, dnsRecord.Name) {
 			recordset := rclient.GetBareDNSRecord(dnsRecord.Name, dnsRecord.Namespace)
 			if err := resourceManager.DeleteResource(&recordset); err != nil {
 				return err
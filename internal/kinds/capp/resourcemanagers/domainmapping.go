@@ -3,8 +3,9 @@ package resourcemanagers
 import (
 	"context"
 	"fmt"
-	"reflect"
+	"slices"
 
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 
 	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
@@ -38,9 +39,11 @@ type KnativeDomainMappingManager struct {
 	EventRecorder record.EventRecorder
 }
 
-// PrepareKnativeDomainMapping creates a new DomainMapping for a Knative service.
-func (k KnativeDomainMappingManager) prepareResource(capp cappv1alpha1.Capp) (knativev1beta1.DomainMapping, error) {
-	dnsConfig, err := utils.GetDNSConfig(k.Ctx, k.K8sclient)
+// prepareResource creates a new DomainMapping of the given hostname. The DomainMapping of a custom hostname
+// points to the Knative Service, while the DomainMapping of a preview points to the Kubernetes Service
+// that Knative creates for the tag of the preview.
+func (k KnativeDomainMappingManager) prepareResource(capp cappv1alpha1.Capp, routeHostname utils.RouteHostname) (knativev1beta1.DomainMapping, error) {
+	dnsConfig, err := utils.GetDNSConfig(k.Ctx, k.K8sclient, capp.Namespace)
 	if err != nil {
 		return knativev1beta1.DomainMapping{}, err
 	}
@@ -50,29 +53,39 @@ func (k KnativeDomainMappingManager) prepareResource(capp cappv1alpha1.Capp) (kn
 		return knativev1beta1.DomainMapping{}, err
 	}
 
-	resourceName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
+	resourceName := utils.GenerateResourceName(routeHostname.Hostname, zone)
 	secretName := utils.GenerateSecretName(resourceName)
 
+	ref := duckv1.KReference{
+		APIVersion: knativev1.SchemeGroupVersion.String(),
+		Name:       capp.Name,
+		Kind:       referenceKind,
+	}
+	if routeHostname.Tag != "" {
+		ref = duckv1.KReference{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Name:       utils.GenerateTaggedServiceName(routeHostname.Tag, capp.Name),
+			Kind:       referenceKind,
+		}
+	}
+
 	knativeDomainMapping := &knativev1beta1.DomainMapping{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      resourceName,
-			Namespace: capp.Namespace,
+			Name:            resourceName,
+			Namespace:       capp.Namespace,
+			OwnerReferences: ownerReferences(capp),
 			Labels: map[string]string{
 				utils.CappResourceKey:   capp.Name,
 				utils.ManagedByLabelKey: utils.CappKey,
 			},
 		},
 		Spec: knativev1beta1.DomainMappingSpec{
-			Ref: duckv1.KReference{
-				APIVersion: knativev1.SchemeGroupVersion.String(),
-				Name:       capp.Name,
-				Kind:       referenceKind,
-			},
+			Ref: ref,
 		},
 	}
 
-	if tlsEnabled := capp.Spec.RouteSpec.TlsEnabled; tlsEnabled {
+	if routeHostname.TlsEnabled {
 		if err := k.setHTTPSKnativeDomainMapping(secretName, capp.Namespace, knativeDomainMapping); err != nil {
 			if !errors.IsNotFound(err) {
 				return *knativeDomainMapping, err
@@ -135,6 +148,25 @@ func (k KnativeDomainMappingManager) IsRequired(capp cappv1alpha1.Capp) bool {
 	return capp.Spec.RouteSpec.Hostname != ""
 }
 
+// RequiresFinalization returns whether the Capp has DomainMappings with TLS. Their TLS secrets are created by
+// cert-manager and are not garbage collected with the Capp, so they are deleted by the Capp finalizer.
+func (k KnativeDomainMappingManager) RequiresFinalization(capp cappv1alpha1.Capp) (bool, error) {
+	if k.IsRequired(capp) && slices.ContainsFunc(utils.GetRouteHostnames(capp.Spec.RouteSpec, rollout.GetTrafficTargets(capp)), func(routeHostname utils.RouteHostname) bool {
+		return routeHostname.TlsEnabled
+	}) {
+		return true, nil
+	}
+
+	domainMappings, err := k.getPreviousDomainMappings(capp)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(domainMappings.Items, func(domainMapping knativev1beta1.DomainMapping) bool {
+		return domainMapping.Spec.TLS != nil
+	}), nil
+}
+
 // Manage creates or updates a DomainMapping resource based on the provided Capp if it's required.
 // If it's not, then it cleans up the resource if it exists.
 func (k KnativeDomainMappingManager) Manage(capp cappv1alpha1.Capp) error {
@@ -145,38 +177,48 @@ func (k KnativeDomainMappingManager) Manage(capp cappv1alpha1.Capp) error {
 	return k.CleanUp(capp)
 }
 
-// createOrUpdate creates or updates a DomainMapping resource.
+// createOrUpdate creates or updates the DomainMapping resources of the Capp hostnames and of its previews.
 func (k KnativeDomainMappingManager) createOrUpdate(capp cappv1alpha1.Capp) error {
-	domainMappingFromCapp, err := k.prepareResource(capp)
-	if err != nil {
-		return fmt.Errorf("failed to prepare DomainMapping: %w", err)
-	}
-
-	domainMapping := knativev1beta1.DomainMapping{}
 	resourceManager := rclient.ResourceManagerClient{Ctx: k.Ctx, K8sclient: k.K8sclient, Log: k.Log}
 
-	if err := k.K8sclient.Get(k.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: domainMappingFromCapp.Name}, &domainMapping); err != nil {
-		if errors.IsNotFound(err) {
-			if err := k.createDomainMapping(capp, domainMappingFromCapp, resourceManager); err != nil {
-				return err
-			}
-		} else {
-			return fmt.Errorf("failed to get DomainMapping %q: %w", domainMappingFromCapp.Name, err)
+	var names []string
+	for _, routeHostname := range utils.GetRouteHostnames(capp.Spec.RouteSpec, rollout.GetTrafficTargets(capp)) {
+		domainMappingFromCapp, err := k.prepareResource(capp, routeHostname)
+		if err != nil {
+			return fmt.Errorf("failed to prepare DomainMapping: %w", err)
 		}
+
+		if err := k.createOrUpdateDomainMapping(capp, domainMappingFromCapp, resourceManager); err != nil {
+			return err
+		}
+		names = append(names, domainMappingFromCapp.Name)
 	}
 
 	if capp.Status.RouteStatus.DomainMappingObjectStatus.URL != nil {
-		if err := k.handlePreviousDomainMappings(capp, resourceManager, domainMappingFromCapp.Name); err != nil {
+		if err := k.handlePreviousDomainMappings(capp, resourceManager, names); err != nil {
 			return fmt.Errorf("failed to delete previous DomainMappings: %w", err)
 		}
 	}
 
-	return k.updateDomainMapping(domainMapping, domainMappingFromCapp, resourceManager)
+	return nil
+}
+
+// createOrUpdateDomainMapping creates the DomainMapping if it does not exist, or applies it otherwise.
+func (k KnativeDomainMappingManager) createOrUpdateDomainMapping(capp cappv1alpha1.Capp, domainMappingFromCapp knativev1beta1.DomainMapping, resourceManager rclient.ResourceManagerClient) error {
+	domainMapping := knativev1beta1.DomainMapping{}
+	if err := k.K8sclient.Get(k.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: domainMappingFromCapp.Name}, &domainMapping); err != nil {
+		if errors.IsNotFound(err) {
+			return k.createDomainMapping(capp, domainMappingFromCapp, resourceManager)
+		}
+		return fmt.Errorf("failed to get DomainMapping %q: %w", domainMappingFromCapp.Name, err)
+	}
+
+	return applyResource(capp, &domainMappingFromCapp, resourceManager, k.EventRecorder)
 }
 
 // createDomainMapping creates a new DomainMapping and emits an event.
 func (k KnativeDomainMappingManager) createDomainMapping(capp cappv1alpha1.Capp, domainMappingFromCapp knativev1beta1.DomainMapping, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(&domainMappingFromCapp); err != nil {
+	if _, err := resourceManager.ApplyResource(&domainMappingFromCapp); err != nil {
 		k.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappDomainMappingCreationFailed,
 			fmt.Sprintf("Failed to create DomainMapping %s", domainMappingFromCapp.Name))
 
@@ -189,24 +231,14 @@ func (k KnativeDomainMappingManager) createDomainMapping(capp cappv1alpha1.Capp,
 	return nil
 }
 
-// updateDomainMapping checks if an update to the DomainMapping is necessary and performs the update to match desired state.
-func (k KnativeDomainMappingManager) updateDomainMapping(knativeDomainMapping, domainMappingFromCapp knativev1beta1.DomainMapping, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(knativeDomainMapping.Spec, domainMappingFromCapp.Spec) {
-		knativeDomainMapping.Spec = domainMappingFromCapp.Spec
-		return resourceManager.UpdateResource(&knativeDomainMapping)
-	}
-
-	return nil
-}
-
-// handlePreviousDomainMappings takes care of removing unneeded DomainMapping objects. If the DNSRecord
-// which corresponds to the latest DomainMapping object is not yet available then return early
-// and do not delete the previous DomainMappings.
-func (k KnativeDomainMappingManager) handlePreviousDomainMappings(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, name string) error {
+// handlePreviousDomainMappings takes care of removing unneeded DomainMapping objects, keeping the ones with the
+// given names. If the DNSRecord which corresponds to the first of these DomainMappings is not yet available
+// then return early and do not delete the previous DomainMappings.
+func (k KnativeDomainMappingManager) handlePreviousDomainMappings(capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient, names []string) error {
 	var available bool
 	var err error
 
-	available, err = utils.IsDNSRecordAvailable(k.Ctx, k.K8sclient, name, capp.Namespace)
+	available, err = utils.IsDNSRecordAvailable(k.Ctx, k.K8sclient, names[0], capp.Namespace)
 	if err != nil {
 		return err
 	}
@@ -220,7 +252,7 @@ func (k KnativeDomainMappingManager) handlePreviousDomainMappings(capp cappv1alp
 		return err
 	}
 
-	return k.deletePreviousDomainMappings(domainMappings, resourceManager, capp.Spec.RouteSpec.Hostname)
+	return k.deletePreviousDomainMappings(domainMappings, resourceManager, names)
 }
 
 // getPreviousDomainMappings returns a list of all DomainMapping objects that are related to the given Capp.
@@ -239,14 +271,17 @@ func (k KnativeDomainMappingManager) getPreviousDomainMappings(capp cappv1alpha1
 	return knativeDomainMappings, nil
 }
 
-// deletePreviousDomainMappings deletes all previous DomainMappings associated with a Capp.
-func (k KnativeDomainMappingManager) deletePreviousDomainMappings(knativeDomainMappings knativev1beta1.DomainMappingList, resourceManager rclient.ResourceManagerClient, hostname string) error {
+// deletePreviousDomainMappings deletes all previous DomainMappings associated with a Capp, and their tls secrets,
+// except for the ones with the given names.
+func (k KnativeDomainMappingManager) deletePreviousDomainMappings(knativeDomainMappings knativev1beta1.DomainMappingList, resourceManager rclient.ResourceManagerClient, names []string) error {
 	for _, domainMapping := range knativeDomainMappings.Items {
-		if domainMapping.Name != hostname {
-			dm := rclient.GetBareDomainMapping(domainMapping.Name, domainMapping.Namespace)
-			if err := resourceManager.DeleteResource(&dm); err != nil {
-				return err
-			}
+		if slices.Contains(names, domainMapping.Name) {
+			continue
+		}
+
+		dm := rclient.GetBareDomainMapping(domainMapping.Name, domainMapping.Namespace)
+		if err := resourceManager.DeleteResource(&dm); err != nil {
+			return err
 		}
 		if err := deleteTLSSecret(resourceManager.Ctx, resourceManager.K8sclient, utils.GenerateSecretName(domainMapping.Name), domainMapping.Namespace); err != nil {
 			return err
@@ -3,7 +3,6 @@ package resourcemanagers
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
@@ -15,16 +14,21 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
+	KedaSource                 = "kedaSource"
 	scaledObjectCreated        = "ScaledObjectCreated"
 	scaledObjectCreationFailed = "ScaledObjectCreationFailed"
 	triggerAuthCreationFailed  = "TriggerAuthenticationFailed"
 	triggerAuthCreated         = "TriggerAuthenticationCreated"
 	kedaKind                   = "ScaledObject"
+	triggerAuthKind            = "TriggerAuthentication"
+	clusterTriggerAuthKind     = "ClusterTriggerAuthentication"
+	clusterTriggerAuthType     = "clusterTriggerAuthentication"
 	kedaAPI                    = "keda.sh/v1alpha1"
 	service                    = "Service"
 	autoscalingClass           = "autoscaling.knative.dev/class"
@@ -46,21 +50,40 @@ func (k KedaSourceManager) prepareResource(capp cappv1alpha1.Capp) ([]kedav1alph
 	var scaledObjects []kedav1alpha1.ScaledObject
 	//nolint:prealloc
 	var triggerAuthentications []kedav1alpha1.TriggerAuthentication
+	preparedTriggerAuths := map[string]bool{}
 
 	for _, source := range capp.Spec.Sources {
 		scaledObject := k.prepareScaledObject(capp, source)
 		scaledObjects = append(scaledObjects, scaledObject)
 
-		triggerAuthentication := k.prepareTriggerAuthentication(capp, source)
-		if triggerAuthentication != nil {
-
-			triggerAuthentications = append(triggerAuthentications, *triggerAuthentication)
+		for _, trigger := range getSourceTriggers(source) {
+			triggerAuthentication := k.prepareTriggerAuthentication(capp, trigger.TriggerAuth)
+			if triggerAuthentication != nil && !preparedTriggerAuths[triggerAuthentication.Name] {
+				preparedTriggerAuths[triggerAuthentication.Name] = true
+				triggerAuthentications = append(triggerAuthentications, *triggerAuthentication)
+			}
 		}
 	}
 
 	return scaledObjects, triggerAuthentications
 }
 
+// getSourceTriggers returns the triggers of a given source. A source which does not define a list
+// of triggers is treated as a single trigger built from its scalar fields.
+func getSourceTriggers(source cappv1alpha1.KedaSource) []cappv1alpha1.KedaTrigger {
+	if len(source.Triggers) > 0 {
+		return source.Triggers
+	}
+
+	return []cappv1alpha1.KedaTrigger{
+		{
+			Type:        source.ScalarType,
+			Metadata:    source.ScalarMetadata,
+			TriggerAuth: source.TriggerAuth,
+		},
+	}
+}
+
 // prepareScaledObject prepares and returns a scaled object from a given capp
 func (k KedaSourceManager) prepareScaledObject(capp cappv1alpha1.Capp, source cappv1alpha1.KedaSource) kedav1alpha1.ScaledObject {
 	scaledObject := kedav1alpha1.ScaledObject{
@@ -69,8 +92,9 @@ func (k KedaSourceManager) prepareScaledObject(capp cappv1alpha1.Capp, source ca
 			APIVersion: kedaAPI,
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      source.Name,
-			Namespace: capp.Namespace,
+			Name:            source.Name,
+			Namespace:       capp.Namespace,
+			OwnerReferences: ownerReferences(capp),
 			Labels: map[string]string{
 				utils.CappResourceKey:   capp.Name,
 				utils.ManagedByLabelKey: utils.CappKey,
@@ -83,41 +107,94 @@ func (k KedaSourceManager) prepareScaledObject(capp cappv1alpha1.Capp, source ca
 				Kind: service,
 				Name: capp.Name,
 			},
-			Triggers: []kedav1alpha1.ScaleTriggers{
-				{
-					Type:     source.ScalarType,
-					Metadata: source.ScalarMetadata,
-				},
-			},
 		},
 	}
 
-	if source.TriggerAuth != nil {
-		scaledObject.Spec.Triggers[0].AuthenticationRef = &kedav1alpha1.AuthenticationRef{
-			Name: source.TriggerAuth.Name,
-		}
+	for _, trigger := range getSourceTriggers(source) {
+		scaledObject.Spec.Triggers = append(scaledObject.Spec.Triggers, prepareScaleTrigger(trigger))
+	}
+
+	if source.Advanced != nil {
+		scaledObject.Spec.PollingInterval = source.Advanced.PollingInterval
+		scaledObject.Spec.CooldownPeriod = source.Advanced.CooldownPeriod
+		scaledObject.Spec.Advanced = prepareAdvancedConfig(*source.Advanced)
 	}
 
 	return scaledObject
 }
 
-// prepareTriggerAuthentication prepares and returns a trigger authentication from a given capp
-func (k KedaSourceManager) prepareTriggerAuthentication(capp cappv1alpha1.Capp, source cappv1alpha1.KedaSource) *kedav1alpha1.TriggerAuthentication {
-	if source.TriggerAuth == nil {
+// prepareScaleTrigger converts a trigger of a Keda source to a ScaledObject trigger.
+func prepareScaleTrigger(trigger cappv1alpha1.KedaTrigger) kedav1alpha1.ScaleTriggers {
+	scaleTrigger := kedav1alpha1.ScaleTriggers{
+		Name:     trigger.Name,
+		Type:     trigger.Type,
+		Metadata: trigger.Metadata,
+	}
+
+	if trigger.TriggerAuth != nil {
+		scaleTrigger.AuthenticationRef = &kedav1alpha1.AuthenticationRef{
+			Name: trigger.TriggerAuth.Name,
+			Kind: triggerAuthKind,
+		}
+
+		if trigger.TriggerAuth.Type == clusterTriggerAuthType {
+			scaleTrigger.AuthenticationRef.Kind = clusterTriggerAuthKind
+		}
+	}
+
+	return scaleTrigger
+}
+
+// prepareAdvancedConfig converts the advanced options of a Keda source to the advanced config of a ScaledObject.
+// It returns nil if none of the options which belong to the advanced config are set.
+func prepareAdvancedConfig(advanced cappv1alpha1.KedaAdvanced) *kedav1alpha1.AdvancedConfig {
+	if advanced.ScalingModifiers == nil && advanced.Behavior == nil {
+		return nil
+	}
+
+	advancedConfig := &kedav1alpha1.AdvancedConfig{}
+
+	if advanced.ScalingModifiers != nil {
+		advancedConfig.ScalingModifiers = kedav1alpha1.ScalingModifiers{
+			Formula:          advanced.ScalingModifiers.Formula,
+			Target:           advanced.ScalingModifiers.Target,
+			ActivationTarget: advanced.ScalingModifiers.ActivationTarget,
+			MetricType:       advanced.ScalingModifiers.MetricType,
+		}
+	}
+
+	if advanced.Behavior != nil {
+		advancedConfig.HorizontalPodAutoscalerConfig = &kedav1alpha1.HorizontalPodAutoscalerConfig{
+			Behavior: advanced.Behavior,
+		}
+	}
+
+	return advancedConfig
+}
+
+// prepareTriggerAuthentication prepares and returns a trigger authentication from a given capp.
+// ClusterTriggerAuthentications are cluster-scoped and are only referenced, so nil is returned for them.
+func (k KedaSourceManager) prepareTriggerAuthentication(capp cappv1alpha1.Capp, triggerAuth *cappv1alpha1.TriggerAuth) *kedav1alpha1.TriggerAuthentication {
+	if triggerAuth == nil || triggerAuth.Type == clusterTriggerAuthType {
 		return nil
 	}
 	triggerAuthentication := &kedav1alpha1.TriggerAuthentication{
 		TypeMeta: metav1.TypeMeta{
-			Kind:       source.TriggerAuth.Type,
+			Kind:       triggerAuthKind,
 			APIVersion: kedaAPI,
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      source.TriggerAuth.Name,
-			Namespace: capp.Namespace,
+			Name:            triggerAuth.Name,
+			Namespace:       capp.Namespace,
+			OwnerReferences: ownerReferences(capp),
+			Labels: map[string]string{
+				utils.CappResourceKey:   capp.Name,
+				utils.ManagedByLabelKey: utils.CappKey,
+			},
 		},
 	}
 
-	for _, secret := range source.TriggerAuth.SecretTargets {
+	for _, secret := range triggerAuth.SecretTargets {
 		triggerAuthentication.Spec.SecretTargetRef = append(triggerAuthentication.Spec.SecretTargetRef, kedav1alpha1.AuthSecretTargetRef{
 			Parameter: secret.Parameter,
 			Name:      secret.SecretRef.Name,
@@ -125,50 +202,59 @@ func (k KedaSourceManager) prepareTriggerAuthentication(capp cappv1alpha1.Capp,
 		})
 	}
 
-	for _, env := range source.TriggerAuth.EnvTargets {
+	for _, env := range triggerAuth.EnvTargets {
 		triggerAuthentication.Spec.Env = append(triggerAuthentication.Spec.Env, kedav1alpha1.AuthEnvironment{
 			Parameter: env.Parameter,
 			Name:      env.Name,
 		})
 	}
 
-	if source.TriggerAuth.PodIdentity != nil {
+	if triggerAuth.PodIdentity != nil {
 		triggerAuthentication.Spec.PodIdentity = &kedav1alpha1.AuthPodIdentity{
-			Provider: kedav1alpha1.PodIdentityProvider(source.TriggerAuth.PodIdentity.Provider),
+			Provider: kedav1alpha1.PodIdentityProvider(triggerAuth.PodIdentity.Provider),
 		}
 	}
 	return triggerAuthentication
 }
 
-// CleanUp attempts to delete the associated scaled object for a given Capp resource.
+// CleanUp attempts to delete the associated ScaledObjects and TriggerAuthentications for a given Capp resource.
 func (k KedaSourceManager) CleanUp(capp cappv1alpha1.Capp) error {
 	resourceManager := rclient.ResourceManagerClient{Ctx: k.Ctx, K8sclient: k.K8sclient, Log: k.Log}
 
-	for _, source := range capp.Spec.Sources {
-		scaledObject := rclient.GetBareScaledObject(capp.Name, capp.Namespace)
+	scaledObjects, err := k.getPreviousScaledObjects(capp)
+	if err != nil {
+		return err
+	}
 
-		if err := resourceManager.DeleteResource(&scaledObject); err != nil {
+	for _, scaledObject := range scaledObjects.Items {
+		so := rclient.GetBareScaledObject(scaledObject.Name, scaledObject.Namespace)
+		if err := resourceManager.DeleteResource(&so); err != nil {
 			if errors.IsNotFound(err) {
-				return nil
+				continue
 			}
 			return err
 		}
+	}
 
-		if source.TriggerAuth != nil {
-			triggerAuth := rclient.GetBareTriggerAuth(capp.Name, capp.Namespace)
-			if err := resourceManager.DeleteResource(&triggerAuth); err != nil {
-				if errors.IsNotFound(err) {
-					return nil
-				}
-				return err
-			}
+	triggerAuths, err := k.getPreviousTriggerAuths(capp)
+	if err != nil {
+		return err
+	}
 
+	for _, triggerAuth := range triggerAuths.Items {
+		ta := rclient.GetBareTriggerAuth(triggerAuth.Name, triggerAuth.Namespace)
+		if err := resourceManager.DeleteResource(&ta); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
 		}
 	}
+
 	return nil
 }
 
-// IsRequired is responsible to determine if resource KafkaSource is required.
+// IsRequired is responsible to determine if resource KedaSource is required.
 func (k KedaSourceManager) IsRequired(capp cappv1alpha1.Capp) bool {
 	return len(capp.Spec.Sources) > 0
 }
@@ -177,41 +263,24 @@ func (k KedaSourceManager) IsRequired(capp cappv1alpha1.Capp) bool {
 // If it's not, then it cleans up the resource if it exists.
 func (k KedaSourceManager) Manage(capp cappv1alpha1.Capp) error {
 	if k.IsRequired(capp) {
-		k.disableKPA(&capp)
 		return k.createOrUpdate(capp)
 	}
 
-	k.enableKPA(&capp)
 	return k.CleanUp(capp)
 }
 
-// enableKPA remove the annotation that disables the KPA
-func (k KedaSourceManager) enableKPA(capp *cappv1alpha1.Capp) {
-	annotations := capp.Spec.ConfigurationSpec.Template.Annotations
-	if annotations == nil {
-		return
-	}
-
-	delete(annotations, autoscalingClass)
-	delete(annotations, autoscalingMetric)
-
-	capp.Spec.ConfigurationSpec.Template.Annotations = annotations
-}
-
-// disableKPA adds an annotation that disables the KPA when a Keda source is created.
-func (k KedaSourceManager) disableKPA(capp *cappv1alpha1.Capp) {
-	annotations := capp.Spec.ConfigurationSpec.Template.Annotations
-	if annotations == nil {
-		annotations = map[string]string{}
+// KedaAutoscalingAnnotations returns the annotations which disable the KPA on the Knative Service
+// so that the replicas are driven by the ScaledObjects of the Capp instead.
+func KedaAutoscalingAnnotations() map[string]string {
+	return map[string]string{
+		autoscalingClass:  hpaAutoscaling,
+		autoscalingMetric: disabled,
 	}
-
-	annotations[autoscalingClass] = hpaAutoscaling
-	annotations[autoscalingMetric] = disabled
-
-	capp.Spec.ConfigurationSpec.Template.Annotations = annotations
 }
 
-// createOrUpdate creates or updates a KafkaSource resource.
+// createOrUpdate creates or updates the ScaledObjects and TriggerAuthentications of a Capp.
+// Objects with the same names which belong to another Capp are not taken over.
+// Objects which belong to sources that were removed from the Capp are deleted.
 func (k KedaSourceManager) createOrUpdate(capp cappv1alpha1.Capp) error {
 	scaledObjects, triggerAuth := k.prepareResource(capp)
 	resourceManager := rclient.ResourceManagerClient{Ctx: k.Ctx, K8sclient: k.K8sclient, Log: k.Log}
@@ -225,9 +294,11 @@ func (k KedaSourceManager) createOrUpdate(capp cappv1alpha1.Capp) error {
 					return err
 				}
 			} else {
-				return fmt.Errorf("failed to get Kafka Source %q: %w", source.Name, err)
+				return fmt.Errorf("failed to get ScaledObject %q: %w", source.Name, err)
 			}
-		} else if err := k.updateScaledObject(existingScaledObject, source, resourceManager); err != nil {
+		} else if existingScaledObject.Labels[utils.CappResourceKey] != capp.Name {
+			return fmt.Errorf("ScaledObject %q already exists and does not belong to Capp %q", source.Name, capp.Name)
+		} else if err := applyResource(capp, &source, resourceManager, k.EventRecorder); err != nil {
 			return err
 		}
 	}
@@ -240,20 +311,101 @@ func (k KedaSourceManager) createOrUpdate(capp cappv1alpha1.Capp) error {
 					return err
 				}
 			} else {
-				return fmt.Errorf("failed to get Kafka Source %q: %w", source.Name, err)
+				return fmt.Errorf("failed to get TriggerAuthentication %q: %w", source.Name, err)
 			}
-		} else if err := k.updateTriggerAuth(existingTriggerAuth, source, resourceManager); err != nil {
+		} else if existingTriggerAuth.Labels[utils.CappResourceKey] != capp.Name {
+			return fmt.Errorf("TriggerAuthentication %q already exists and does not belong to Capp %q", source.Name, capp.Name)
+		} else if err := applyResource(capp, &source, resourceManager, k.EventRecorder); err != nil {
+			return err
+		}
+
+	}
+
+	return k.deletePreviousResources(capp, scaledObjects, triggerAuth, resourceManager)
+}
+
+// deletePreviousResources deletes the ScaledObjects and TriggerAuthentications of a Capp which are no longer desired.
+func (k KedaSourceManager) deletePreviousResources(capp cappv1alpha1.Capp, scaledObjects []kedav1alpha1.ScaledObject, triggerAuths []kedav1alpha1.TriggerAuthentication, resourceManager rclient.ResourceManagerClient) error {
+	desiredScaledObjects := map[string]bool{}
+	for _, scaledObject := range scaledObjects {
+		desiredScaledObjects[scaledObject.Name] = true
+	}
+
+	previousScaledObjects, err := k.getPreviousScaledObjects(capp)
+	if err != nil {
+		return err
+	}
+
+	for _, scaledObject := range previousScaledObjects.Items {
+		if desiredScaledObjects[scaledObject.Name] {
+			continue
+		}
+		so := rclient.GetBareScaledObject(scaledObject.Name, scaledObject.Namespace)
+		if err := resourceManager.DeleteResource(&so); err != nil && !errors.IsNotFound(err) {
 			return err
 		}
+	}
 
+	desiredTriggerAuths := map[string]bool{}
+	for _, triggerAuth := range triggerAuths {
+		desiredTriggerAuths[triggerAuth.Name] = true
+	}
+
+	previousTriggerAuths, err := k.getPreviousTriggerAuths(capp)
+	if err != nil {
+		return err
+	}
+
+	for _, triggerAuth := range previousTriggerAuths.Items {
+		if desiredTriggerAuths[triggerAuth.Name] {
+			continue
+		}
+		ta := rclient.GetBareTriggerAuth(triggerAuth.Name, triggerAuth.Namespace)
+		if err := resourceManager.DeleteResource(&ta); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// getPreviousScaledObjects returns a list of all ScaledObject objects that are related to the given Capp.
+func (k KedaSourceManager) getPreviousScaledObjects(capp cappv1alpha1.Capp) (kedav1alpha1.ScaledObjectList, error) {
+	scaledObjects := kedav1alpha1.ScaledObjectList{}
+
+	set := labels.Set{
+		utils.CappResourceKey: capp.Name,
+	}
+	listOptions := utils.GetListOptions(set)
+	listOptions.Namespace = capp.Namespace
+
+	if err := k.K8sclient.List(k.Ctx, &scaledObjects, &listOptions); err != nil {
+		return scaledObjects, fmt.Errorf("unable to list ScaledObjects of Capp %q: %w", capp.Name, err)
+	}
+
+	return scaledObjects, nil
+}
+
+// getPreviousTriggerAuths returns a list of all TriggerAuthentication objects that are related to the given Capp.
+func (k KedaSourceManager) getPreviousTriggerAuths(capp cappv1alpha1.Capp) (kedav1alpha1.TriggerAuthenticationList, error) {
+	triggerAuths := kedav1alpha1.TriggerAuthenticationList{}
+
+	set := labels.Set{
+		utils.CappResourceKey: capp.Name,
+	}
+	listOptions := utils.GetListOptions(set)
+	listOptions.Namespace = capp.Namespace
+
+	if err := k.K8sclient.List(k.Ctx, &triggerAuths, &listOptions); err != nil {
+		return triggerAuths, fmt.Errorf("unable to list TriggerAuthentications of Capp %q: %w", capp.Name, err)
+	}
+
+	return triggerAuths, nil
+}
+
 // createTriggerAuth creates a new Trigger Authentication and emits an event.
 func (k KedaSourceManager) createTriggerAuth(capp *cappv1alpha1.Capp, triggerAuth *kedav1alpha1.TriggerAuthentication, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(triggerAuth); err != nil {
+	if _, err := resourceManager.ApplyResource(triggerAuth); err != nil {
 		k.EventRecorder.Event(capp, corev1.EventTypeWarning, triggerAuthCreationFailed,
 			fmt.Sprintf("Failed to create Trigger Authentication %s", triggerAuth.Name))
 		return err
@@ -265,19 +417,9 @@ func (k KedaSourceManager) createTriggerAuth(capp *cappv1alpha1.Capp, triggerAut
 	return nil
 }
 
-// updateTriggerAuth checks if an update to the scaled object is necessary and performs the update to match desired state.
-func (k KedaSourceManager) updateTriggerAuth(existingtriggerAuth, triggerAuth kedav1alpha1.TriggerAuthentication, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(existingtriggerAuth.Spec, triggerAuth.Spec) {
-		existingtriggerAuth.Spec = triggerAuth.Spec
-		return resourceManager.UpdateResource(&existingtriggerAuth)
-	}
-
-	return nil
-}
-
 // createScaledObject creates a new Scaled Object and emits an event.
 func (k KedaSourceManager) createScaledObject(capp *cappv1alpha1.Capp, scaledObject *kedav1alpha1.ScaledObject, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(scaledObject); err != nil {
+	if _, err := resourceManager.ApplyResource(scaledObject); err != nil {
 		k.EventRecorder.Event(capp, corev1.EventTypeWarning, scaledObjectCreationFailed,
 			fmt.Sprintf("Failed to create Scaled Object %s", scaledObject.Name))
 		return err
@@ -288,13 +430,3 @@ func (k KedaSourceManager) createScaledObject(capp *cappv1alpha1.Capp, scaledObj
 
 	return nil
 }
-
-// updateKafkaSource checks if an update to the scaled object is necessary and performs the update to match desired state.
-func (k KedaSourceManager) updateScaledObject(existingScaledObject, scaledObject kedav1alpha1.ScaledObject, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(existingScaledObject.Spec, scaledObject.Spec) {
-		existingScaledObject.Spec = scaledObject.Spec
-		return resourceManager.UpdateResource(&existingScaledObject)
-	}
-
-	return nil
-}
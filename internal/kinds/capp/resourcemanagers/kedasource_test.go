@@ -0,0 +1,74 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKedaSourceManagerNameCollision(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, kedav1alpha1.AddToScheme(scheme))
+
+	newCapp := func(name, sourceName, triggerAuthName string) cappv1alpha1.Capp {
+		return cappv1alpha1.Capp{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+			Spec: cappv1alpha1.CappSpec{
+				Sources: []cappv1alpha1.KedaSource{{
+					Name:           sourceName,
+					ScalarType:     "kafka",
+					ScalarMetadata: map[string]string{"topic": name},
+					TriggerAuth:    &cappv1alpha1.TriggerAuth{Type: "triggerAuthentication", Name: triggerAuthName},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		capp          cappv1alpha1.Capp
+		errorContains string
+	}{
+		{
+			name:          "ScaledObject of another Capp",
+			capp:          newCapp("api", "kafka", "api-auth"),
+			errorContains: `ScaledObject "kafka" already exists and does not belong to Capp "api"`,
+		},
+		{
+			name:          "TriggerAuthentication of another Capp",
+			capp:          newCapp("api", "api-kafka", "kafka-auth"),
+			errorContains: `TriggerAuthentication "kafka-auth" already exists and does not belong to Capp "api"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			manager := KedaSourceManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+
+			assert.NoError(t, manager.Manage(newCapp("web", "kafka", "kafka-auth")))
+			assert.ErrorContains(t, manager.Manage(tt.capp), tt.errorContains)
+
+			scaledObject := kedav1alpha1.ScaledObject{}
+			assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "kafka"}, &scaledObject))
+			assert.Equal(t, "web", scaledObject.Labels[utils.CappResourceKey])
+			assert.Equal(t, "web", scaledObject.Spec.Triggers[0].Metadata["topic"], "Expected the ScaledObject of the other Capp to be left untouched")
+
+			assert.NoError(t, manager.CleanUp(tt.capp))
+			triggerAuth := kedav1alpha1.TriggerAuthentication{}
+			assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "kafka-auth"}, &triggerAuth),
+				"Expected the clean up not to delete the objects of the other Capp")
+		})
+	}
+}
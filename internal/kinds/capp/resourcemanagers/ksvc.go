@@ -3,10 +3,10 @@ package resourcemanagers
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/autoscale"
 	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
@@ -39,7 +39,7 @@ type KnativeServiceManager struct {
 }
 
 // prepareResource generates a Knative Service definition from a given Capp resource.
-func (k KnativeServiceManager) prepareResource(capp cappv1alpha1.Capp, ctx context.Context) knativev1.Service {
+func (k KnativeServiceManager) prepareResource(capp cappv1alpha1.Capp, ctx context.Context) (knativev1.Service, error) {
 	knativeServiceAnnotations := utils.FilterKeysWithoutPrefix(capp.Annotations, utils.CappAPIGroup)
 	knativeServiceLabels := map[string]string{}
 
@@ -51,8 +51,9 @@ func (k KnativeServiceManager) prepareResource(capp cappv1alpha1.Capp, ctx conte
 	knativeService := knativev1.Service{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      capp.Name,
-			Namespace: capp.Namespace,
+			Name:            capp.Name,
+			Namespace:       capp.Namespace,
+			OwnerReferences: ownerReferences(capp),
 			Labels: map[string]string{
 				utils.CappResourceKey:   capp.Name,
 				utils.ManagedByLabelKey: utils.CappKey,
@@ -61,6 +62,9 @@ func (k KnativeServiceManager) prepareResource(capp cappv1alpha1.Capp, ctx conte
 		},
 		Spec: knativev1.ServiceSpec{
 			ConfigurationSpec: capp.Spec.ConfigurationSpec,
+			RouteSpec: knativev1.RouteSpec{
+				Traffic: rollout.GetTrafficTargets(capp),
+			},
 		},
 	}
 
@@ -74,15 +78,18 @@ func (k KnativeServiceManager) prepareResource(capp cappv1alpha1.Capp, ctx conte
 	volumes := k.prepareVolumes(capp)
 	knativeService.Spec.Template.Spec.Volumes = append(knativeService.Spec.Template.Spec.Volumes, volumes...)
 
-	cappConfig, err := utils.GetCappConfig(k.K8sclient)
+	cappConfig, err := utils.ResolveCappConfig(ctx, k.K8sclient, capp.Namespace)
 	if err != nil {
-		k.Log.Error(err, fmt.Sprintf("could not fetch cappConfig from namespace %q", utils.CappNS))
+		return knativev1.Service{}, fmt.Errorf("could not resolve cappConfig of namespace %q: %w", capp.Namespace, err)
 	}
 
 	knativeService.Spec.Template.Annotations = utils.MergeMaps(knativeServiceAnnotations, autoscale.SetAutoScaler(capp, cappConfig.Spec.AutoscaleConfig))
+	if len(capp.Spec.Sources) > 0 {
+		knativeService.Spec.Template.Annotations = utils.MergeMaps(knativeService.Spec.Template.Annotations, KedaAutoscalingAnnotations())
+	}
 	knativeService.Spec.Template.Labels = knativeServiceLabels
 
-	return knativeService
+	return knativeService, nil
 }
 
 // prepareVolumes generates a list of volumes to be used in a Knative Service definition from a given Capp resource.
@@ -147,7 +154,11 @@ func (k KnativeServiceManager) Manage(capp cappv1alpha1.Capp) error {
 
 // createOrUpdate creates or updates a KSVC resource.
 func (k KnativeServiceManager) createOrUpdate(capp cappv1alpha1.Capp) error {
-	knativeServiceFromCapp := k.prepareResource(capp, k.Ctx)
+	knativeServiceFromCapp, err := k.prepareResource(capp, k.Ctx)
+	if err != nil {
+		return fmt.Errorf("failed to prepare KnativeService: %w", err)
+	}
+
 	knativeService := knativev1.Service{}
 	resourceManager := rclient.ResourceManagerClient{Ctx: k.Ctx, K8sclient: k.K8sclient, Log: k.Log}
 
@@ -167,12 +178,12 @@ func (k KnativeServiceManager) createOrUpdate(capp cappv1alpha1.Capp) error {
 		}
 	}
 
-	return k.updateKSVC(&knativeService, &knativeServiceFromCapp, resourceManager)
+	return applyResource(capp, &knativeServiceFromCapp, resourceManager, k.EventRecorder)
 }
 
 // createKSVC creates a new knativeService and emits an event.
 func (k KnativeServiceManager) createKSVC(capp *cappv1alpha1.Capp, knativeServiceFromCapp *knativev1.Service, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(knativeServiceFromCapp); err != nil {
+	if _, err := resourceManager.ApplyResource(knativeServiceFromCapp); err != nil {
 		k.EventRecorder.Event(capp, corev1.EventTypeWarning, eventCappKnativeServiceCreationFailed,
 			fmt.Sprintf("Failed to create KnativeService %s", knativeServiceFromCapp.Name))
 		return err
@@ -183,13 +194,3 @@ func (k KnativeServiceManager) createKSVC(capp *cappv1alpha1.Capp, knativeServic
 
 	return nil
 }
-
-// updateKSVC checks if an update to the KnativeService is necessary and performs the update to match desired state.
-func (k KnativeServiceManager) updateKSVC(knativeService, knativeServiceFromCapp *knativev1.Service, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(knativeService.Spec, knativeServiceFromCapp.Spec) {
-		knativeService.Spec = knativeServiceFromCapp.Spec
-		return resourceManager.UpdateResource(knativeService)
-	}
-
-	return nil
-}
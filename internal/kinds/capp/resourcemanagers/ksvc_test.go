@@ -0,0 +1,38 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKnativeServiceManagerManageWithoutCappConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, knativev1.AddToScheme(scheme))
+
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+		Spec:       cappv1alpha1.CappSpec{State: cappEnabledState},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	manager := KnativeServiceManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+
+	err := manager.Manage(capp)
+	assert.ErrorContains(t, err, "could not resolve cappConfig")
+
+	knativeServices := knativev1.ServiceList{}
+	assert.NoError(t, k8sClient.List(context.Background(), &knativeServices))
+	assert.Empty(t, knativeServices.Items, "Expected no KnativeService to be created without a CappConfig")
+}
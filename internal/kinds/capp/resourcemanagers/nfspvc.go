@@ -3,7 +3,6 @@ package resourcemanagers
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 
@@ -42,8 +41,9 @@ func (n NFSPVCManager) prepareResource(capp cappv1alpha1.Capp) []nfspvcv1alpha1.
 	for _, nfsVolume := range capp.Spec.VolumesSpec.NFSVolumes {
 		nfsPvc := nfspvcv1alpha1.NfsPvc{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      nfsVolume.Name,
-				Namespace: capp.Namespace,
+				Name:            nfsVolume.Name,
+				Namespace:       capp.Namespace,
+				OwnerReferences: ownerReferences(capp),
 				Labels: map[string]string{
 					utils.CappResourceKey:   capp.Name,
 					utils.ManagedByLabelKey: utils.CappKey,
@@ -134,7 +134,7 @@ func (n NFSPVCManager) createOrUpdate(capp cappv1alpha1.Capp) error {
 			} else {
 				return fmt.Errorf("failed to get NFSPVC %q: %w", nfspvc.Name, err)
 			}
-		} else if err := n.updateNFSPVC(existingNFSPVC, nfspvc, resourceManager); err != nil {
+		} else if err := applyResource(capp, &nfspvc, resourceManager, n.EventRecorder); err != nil {
 			return err
 		}
 	}
@@ -144,7 +144,7 @@ func (n NFSPVCManager) createOrUpdate(capp cappv1alpha1.Capp) error {
 
 // createKSVC creates a new NFSPVC and emits an event.
 func (n NFSPVCManager) createNFSPVC(capp *cappv1alpha1.Capp, nfspvc *nfspvcv1alpha1.NfsPvc, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(nfspvc); err != nil {
+	if _, err := resourceManager.ApplyResource(nfspvc); err != nil {
 		n.EventRecorder.Event(capp, corev1.EventTypeWarning, eventNFSPVCCreationFailed,
 			fmt.Sprintf("Failed to create NFSPVC %s", nfspvc.Name))
 		return err
@@ -155,13 +155,3 @@ func (n NFSPVCManager) createNFSPVC(capp *cappv1alpha1.Capp, nfspvc *nfspvcv1alp
 
 	return nil
 }
-
-// updateNFSPVC checks if an update to the NFSPVC is necessary and performs the update to match desired state.
-func (n NFSPVCManager) updateNFSPVC(existingNFSPVC, nfspvc nfspvcv1alpha1.NfsPvc, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(existingNFSPVC.Spec, nfspvc.Spec) {
-		existingNFSPVC.Spec = nfspvc.Spec
-		return resourceManager.UpdateResource(&existingNFSPVC)
-	}
-
-	return nil
-}
@@ -1,6 +1,28 @@
 package resourcemanagers
 
-import cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+import (
+	"fmt"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
+	"github.com/dana-team/container-app-operator/internal/metrics"
+	nfspvcv1alpha1 "github.com/dana-team/nfspvc-operator/api/v1alpha1"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	eventDriftDetected = "DriftDetected"
+	cappKind           = "Capp"
+)
 
 // ResourceManager is an interface for every resource managed by Capp.
 type ResourceManager interface {
@@ -8,3 +30,57 @@ type ResourceManager interface {
 	CleanUp(capp cappv1alpha1.Capp) error
 	IsRequired(capp cappv1alpha1.Capp) bool
 }
+
+// FinalizationChecker is implemented by resource managers whose resources are not all garbage collected
+// with the Capp, such as resources in other namespaces or resources created by other controllers.
+// These resources are cleaned up by the Capp finalizer.
+type FinalizationChecker interface {
+	RequiresFinalization(capp cappv1alpha1.Capp) (bool, error)
+}
+
+// RequiresFinalization returns whether the given manager has resources of the Capp which must be cleaned up by
+// the Capp finalizer. A manager which does not implement FinalizationChecker relies on garbage collection only.
+func RequiresFinalization(manager ResourceManager, capp cappv1alpha1.Capp) (bool, error) {
+	checker, ok := manager.(FinalizationChecker)
+	if !ok {
+		return false, nil
+	}
+
+	return checker.RequiresFinalization(capp)
+}
+
+// ChildLists returns empty lists of every kind of resource which the resource managers create in the namespace
+// of a Capp, all of which are labeled with the name of the Capp.
+func ChildLists() []client.ObjectList {
+	return []client.ObjectList{
+		&knativev1.ServiceList{},
+		&knativev1beta1.DomainMappingList{},
+		&dnsrecordv1alpha1.CNAMERecordList{},
+		&cmapi.CertificateList{},
+		&nfspvcv1alpha1.NfsPvcList{},
+		&kedav1alpha1.ScaledObjectList{},
+		&kedav1alpha1.TriggerAuthenticationList{},
+		&loggingv1beta1.SyslogNGFlowList{},
+		&loggingv1beta1.SyslogNGOutputList{},
+	}
+}
+
+// ownerReferences returns the owner references of a child resource in the namespace of the Capp. The Capp is
+// set as the controller of the resource, so that the resource is garbage collected once the Capp is deleted.
+func ownerReferences(capp cappv1alpha1.Capp) []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(&capp, cappv1alpha1.GroupVersion.WithKind(cappKind))}
+}
+
+// applyResource applies a child resource of the Capp using server-side apply. If fields managed by the
+// operator were changed by someone else, an event is emitted and the drift is counted before it is corrected.
+func applyResource(capp cappv1alpha1.Capp, resource client.Object, resourceManager rclient.ResourceManagerClient, eventRecorder record.EventRecorder) error {
+	drifted, err := resourceManager.ApplyResource(resource)
+	if drifted {
+		kind := resource.GetObjectKind().GroupVersionKind().Kind
+		eventRecorder.Event(&capp, corev1.EventTypeWarning, eventDriftDetected,
+			fmt.Sprintf("Fields of %s %s managed by the Capp were changed, restoring them", kind, resource.GetName()))
+		metrics.ResourceDrifts.WithLabelValues(kind).Inc()
+	}
+
+	return err
+}
@@ -3,7 +3,8 @@ package resourcemanagers
 import (
 	"context"
 	"fmt"
-	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 
@@ -15,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 
@@ -26,8 +28,30 @@ const (
 	eventCappSyslogNGFlowCreationFailed = "SyslogNGFlowCreationFailed"
 	eventCappSyslogNGFlowCreated        = "SyslogNGFlowCreated"
 	knativeConfiguration                = "serving.knative.dev/configuration"
+	logMessageField                     = "json#log"
+	defaultParserPrefix                 = "json#fields#"
+	matchTypeString                     = "string"
+	matchTypePCRE                       = "pcre"
+	severityInfo                        = "info"
+	severityWarning                     = "warning"
+	severityError                       = "error"
+	severityCritical                    = "critical"
 )
 
+// severityKeywords maps each severity to the level keywords of the log lines with the severity.
+var severityKeywords = map[string][]string{
+	severityInfo:     {"info"},
+	severityWarning:  {"warn", "warning"},
+	severityError:    {"err", "error"},
+	severityCritical: {"crit", "critical", "fatal", "panic", "alert", "emerg", "emergency"},
+}
+
+// levelFields are the names of the fields which hold the level of structured log lines.
+var levelFields = []string{"level", "lvl", "severity"}
+
+// severities are the severities in ascending order.
+var severities = []string{severityInfo, severityWarning, severityError, severityCritical}
+
 type SyslogNGFlowManager struct {
 	Ctx           context.Context
 	K8sclient     client.Client
@@ -35,15 +59,16 @@ type SyslogNGFlowManager struct {
 	EventRecorder record.EventRecorder
 }
 
-// prepareResource prepares a SyslogNGFlow resource based on the provided Capp.
-func (f SyslogNGFlowManager) prepareResource(capp cappv1alpha1.Capp) loggingv1beta1.SyslogNGFlow {
-	syslogNGFlowName := capp.GetName()
-	syslogNGOutputName := capp.GetName()
+// prepareResource prepares a SyslogNGFlow resource for the given log destination of the provided Capp.
+// The flow ships the logs of the Capp which pass the filters of the destination to its SyslogNGOutput.
+func (f SyslogNGFlowManager) prepareResource(capp cappv1alpha1.Capp, destination cappv1alpha1.LogDestination) loggingv1beta1.SyslogNGFlow {
+	name := utils.GetLogDestinationResourceName(capp.GetName(), destination.Name)
 
 	syslogNGFlow := loggingv1beta1.SyslogNGFlow{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      syslogNGFlowName,
-			Namespace: capp.GetNamespace(),
+			Name:            name,
+			Namespace:       capp.GetNamespace(),
+			OwnerReferences: ownerReferences(capp),
 			Labels: map[string]string{
 				utils.CappResourceKey:   capp.Name,
 				utils.ManagedByLabelKey: utils.CappKey,
@@ -53,66 +78,169 @@ func (f SyslogNGFlowManager) prepareResource(capp cappv1alpha1.Capp) loggingv1be
 			Match: &loggingv1beta1.SyslogNGMatch{
 				Regexp: &filter.RegexpMatchExpr{
 					Pattern: capp.GetName(),
-					Type:    "string",
+					Type:    matchTypeString,
 					Value:   fmt.Sprintf("json#kubernetes#labels#%s", knativeConfiguration),
 				},
 			},
-			LocalOutputRefs: []string{syslogNGOutputName},
+			Filters:         buildSyslogNGFilters(destination.LogSpec),
+			LocalOutputRefs: []string{name},
 		},
 	}
 	return syslogNGFlow
 }
 
-// CleanUp attempts to delete the associated SyslogNGFlow for a given Capp resource.
+// buildSyslogNGFilters returns the filter and parser stanzas of the flow of the given logSpec.
+// The filters are applied before the parser, so that only shipped log lines are parsed.
+func buildSyslogNGFilters(logSpec cappv1alpha1.LogSpec) []loggingv1beta1.SyslogNGFilter {
+	var filters []loggingv1beta1.SyslogNGFilter
+
+	if logFilters := logSpec.Filters; logFilters != nil {
+		if len(logFilters.Include) > 0 {
+			match := matchAny(logFilters.Include)
+			filters = append(filters, loggingv1beta1.SyslogNGFilter{Match: (*filter.MatchConfig)(&match)})
+		}
+
+		if len(logFilters.Exclude) > 0 {
+			match := matchAny(logFilters.Exclude)
+			filters = append(filters, loggingv1beta1.SyslogNGFilter{Match: &filter.MatchConfig{Not: &match}})
+		}
+
+		if logFilters.MinSeverity != "" {
+			match := matchMessage(severityPattern(logFilters.MinSeverity))
+			filters = append(filters, loggingv1beta1.SyslogNGFilter{Match: (*filter.MatchConfig)(&match)})
+		}
+	}
+
+	if logParser := logSpec.Parser; logParser != nil {
+		prefix := defaultParserPrefix
+		if logParser.Prefix != "" {
+			prefix = logParser.Prefix
+		}
+
+		filters = append(filters, loggingv1beta1.SyslogNGFilter{
+			Parser: &filter.ParserConfig{
+				Regexp: &filter.RegexpParser{
+					Patterns: logParser.Patterns,
+					Prefix:   prefix,
+					Template: fmt.Sprintf("${%s}", logMessageField),
+				},
+			},
+		})
+	}
+
+	return filters
+}
+
+// matchAny returns a match expression which matches the log lines matching any of the given patterns.
+func matchAny(patterns []string) filter.MatchExpr {
+	if len(patterns) == 1 {
+		return matchMessage(patterns[0])
+	}
+
+	match := filter.MatchExpr{}
+	for _, pattern := range patterns {
+		match.Or = append(match.Or, matchMessage(pattern))
+	}
+	return match
+}
+
+// matchMessage returns a match expression which matches the log lines matching the given pattern.
+func matchMessage(pattern string) filter.MatchExpr {
+	return filter.MatchExpr{
+		Regexp: &filter.RegexpMatchExpr{
+			Pattern: pattern,
+			Type:    matchTypePCRE,
+			Value:   logMessageField,
+		},
+	}
+}
+
+// severityPattern returns a case-insensitive pattern which matches the log lines with a level of the given severity and
+// above. The level is either the leading token of the line, e.g. "ERROR ..." or "[warn] ...", or the value of a
+// level field, e.g. "level":"error" or level=error, so that a level keyword in the message itself is not matched.
+func severityPattern(minSeverity string) string {
+	index := max(slices.Index(severities, minSeverity), 0)
+
+	var keywords []string
+	for _, severity := range severities[index:] {
+		keywords = append(keywords, severityKeywords[severity]...)
+	}
+	return fmt.Sprintf(`(?i)(^[\s\[<]*|\b(%s)["']?\s*[:=]\s*["']?)(%s)\b`, strings.Join(levelFields, "|"), strings.Join(keywords, "|"))
+}
+
+// CleanUp attempts to delete the associated SyslogNGFlows for a given Capp resource.
 func (f SyslogNGFlowManager) CleanUp(capp cappv1alpha1.Capp) error {
 	resourceManager := rclient.ResourceManagerClient{Ctx: f.Ctx, K8sclient: f.K8sclient, Log: f.Log}
-	syslogNGFlow := rclient.GetBareSyslogNGFlow(capp.Name, capp.Namespace)
 
-	if err := resourceManager.DeleteResource(&syslogNGFlow); err != nil {
-		if errors.IsNotFound(err) {
-			return nil
-		}
+	syslogNGFlows, err := f.getPreviousSyslogNGFlows(capp)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return f.deletePreviousSyslogNGFlows(syslogNGFlows, resourceManager, []string{})
 }
 
 // IsRequired is responsible to determine if resource logging operator SyslogNGFlow is required.
 func (f SyslogNGFlowManager) IsRequired(capp cappv1alpha1.Capp) bool {
-	return capp.Spec.LogSpec != cappv1alpha1.LogSpec{}
+	return len(utils.GetLogDestinations(capp)) > 0
 }
 
-// Manage creates or updates a SyslogNGFlow resource based on the provided Capp if it's required.
-// If it's not, then it cleans up the resource if it exists.
+// Manage creates or updates a SyslogNGFlow resource for every log destination of the provided Capp
+// if it's required, and deletes the SyslogNGFlows of removed destinations.
+// If it's not, then it cleans up the resources if they exist.
 func (f SyslogNGFlowManager) Manage(capp cappv1alpha1.Capp) error {
 	if f.IsRequired(capp) {
-		return f.createOrUpdate(capp)
+		return f.create(capp)
 	}
 
 	return f.CleanUp(capp)
 }
 
-// createOrUpdate creates or updates a SyslogNGFlow resource.
-func (f SyslogNGFlowManager) createOrUpdate(capp cappv1alpha1.Capp) error {
-	syslogNGFlowFromCapp := f.prepareResource(capp)
-	syslogNGFlow := loggingv1beta1.SyslogNGFlow{}
+// create creates or updates the SyslogNGFlows of the log destinations of the Capp,
+// and deletes the SyslogNGFlows of removed destinations.
+func (f SyslogNGFlowManager) create(capp cappv1alpha1.Capp) error {
 	resourceManager := rclient.ResourceManagerClient{Ctx: f.Ctx, K8sclient: f.K8sclient, Log: f.Log}
 
+	var names []string
+	for _, destination := range utils.GetLogDestinations(capp) {
+		if err := f.createOrUpdate(capp, destination, resourceManager); err != nil {
+			return err
+		}
+		names = append(names, utils.GetLogDestinationResourceName(capp.Name, destination.Name))
+	}
+
+	syslogNGFlows, err := f.getPreviousSyslogNGFlows(capp)
+	if err != nil {
+		return err
+	}
+
+	return f.deletePreviousSyslogNGFlows(syslogNGFlows, resourceManager, names)
+}
+
+// createOrUpdate creates or updates the SyslogNGFlow resource of the given log destination. A SyslogNGFlow
+// with the same name which belongs to another Capp is not taken over.
+func (f SyslogNGFlowManager) createOrUpdate(capp cappv1alpha1.Capp, destination cappv1alpha1.LogDestination, resourceManager rclient.ResourceManagerClient) error {
+	syslogNGFlowFromCapp := f.prepareResource(capp, destination)
+	syslogNGFlow := loggingv1beta1.SyslogNGFlow{}
+
 	if err := f.K8sclient.Get(f.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: syslogNGFlowFromCapp.Name}, &syslogNGFlow); err != nil {
 		if errors.IsNotFound(err) {
 			return f.createSyslogNGFlow(syslogNGFlowFromCapp, capp, resourceManager)
 		} else {
-			return fmt.Errorf("failed to get SyslogNGFlow %q: %w", syslogNGFlow.Name, err)
+			return fmt.Errorf("failed to get SyslogNGFlow %q: %w", syslogNGFlowFromCapp.Name, err)
 		}
 	}
 
-	return f.updateSyslogNGFlow(&syslogNGFlow, &syslogNGFlowFromCapp, resourceManager)
+	if syslogNGFlow.Labels[utils.CappResourceKey] != capp.Name {
+		return fmt.Errorf("SyslogNGFlow %q already exists and does not belong to Capp %q", syslogNGFlowFromCapp.Name, capp.Name)
+	}
+
+	return applyResource(capp, &syslogNGFlowFromCapp, resourceManager, f.EventRecorder)
 }
 
 // createSyslogNGFlow creates a new SyslogNGFlow and emits an event.
 func (f SyslogNGFlowManager) createSyslogNGFlow(syslogNGFlowFromCapp loggingv1beta1.SyslogNGFlow, capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(&syslogNGFlowFromCapp); err != nil {
+	if _, err := resourceManager.ApplyResource(&syslogNGFlowFromCapp); err != nil {
 		f.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappSyslogNGFlowCreationFailed,
 			fmt.Sprintf("Failed to create SyslogNGFlow %s", syslogNGFlowFromCapp.Name))
 		return err
@@ -124,11 +252,33 @@ func (f SyslogNGFlowManager) createSyslogNGFlow(syslogNGFlowFromCapp loggingv1be
 	return nil
 }
 
-// updateSyslogNGFlow checks if an update to the SyslogNGFlow is necessary and performs the update to match desired state.
-func (f SyslogNGFlowManager) updateSyslogNGFlow(syslogNGFlow, syslogNGFlowFromCapp *loggingv1beta1.SyslogNGFlow, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(syslogNGFlow.Spec, syslogNGFlowFromCapp.Spec) {
-		syslogNGFlow.Spec = syslogNGFlowFromCapp.Spec
-		return resourceManager.UpdateResource(syslogNGFlow)
+// getPreviousSyslogNGFlows returns a list of all SyslogNGFlow objects that are related to the given Capp.
+func (f SyslogNGFlowManager) getPreviousSyslogNGFlows(capp cappv1alpha1.Capp) (loggingv1beta1.SyslogNGFlowList, error) {
+	syslogNGFlows := loggingv1beta1.SyslogNGFlowList{}
+
+	set := labels.Set{
+		utils.CappResourceKey:   capp.Name,
+		utils.ManagedByLabelKey: utils.CappKey,
+	}
+	listOptions := utils.GetListOptions(set)
+	listOptions.Namespace = capp.Namespace
+
+	if err := f.K8sclient.List(f.Ctx, &syslogNGFlows, &listOptions); err != nil {
+		return syslogNGFlows, fmt.Errorf("unable to list SyslogNGFlows of Capp %q: %w", capp.Name, err)
+	}
+
+	return syslogNGFlows, nil
+}
+
+// deletePreviousSyslogNGFlows deletes all previous SyslogNGFlows associated with a Capp, except for the ones with the given names.
+func (f SyslogNGFlowManager) deletePreviousSyslogNGFlows(syslogNGFlows loggingv1beta1.SyslogNGFlowList, resourceManager rclient.ResourceManagerClient, names []string) error {
+	for _, item := range syslogNGFlows.Items {
+		if !slices.Contains(names, item.Name) {
+			syslogNGFlow := rclient.GetBareSyslogNGFlow(item.Name, item.Namespace)
+			if err := resourceManager.DeleteResource(&syslogNGFlow); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
 	}
 
 	return nil
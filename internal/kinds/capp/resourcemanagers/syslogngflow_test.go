@@ -0,0 +1,216 @@
+package resourcemanagers
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuildSyslogNGFilters(t *testing.T) {
+	t.Run("No filters and no parser", func(t *testing.T) {
+		assert.Empty(t, buildSyslogNGFilters(cappv1alpha1.LogSpec{Type: logTypeSyslog, Host: "syslog.example.com"}))
+	})
+
+	t.Run("Include filter with a single pattern", func(t *testing.T) {
+		filters := buildSyslogNGFilters(cappv1alpha1.LogSpec{Filters: &cappv1alpha1.LogFilters{Include: []string{"payment"}}})
+		assert.Len(t, filters, 1)
+		assert.Equal(t, "payment", filters[0].Match.Regexp.Pattern)
+		assert.Equal(t, matchTypePCRE, filters[0].Match.Regexp.Type)
+		assert.Equal(t, logMessageField, filters[0].Match.Regexp.Value)
+	})
+
+	t.Run("Include filter with several patterns matches any of them", func(t *testing.T) {
+		filters := buildSyslogNGFilters(cappv1alpha1.LogSpec{Filters: &cappv1alpha1.LogFilters{Include: []string{"payment", "refund"}}})
+		assert.Len(t, filters, 1)
+		assert.Nil(t, filters[0].Match.Regexp)
+		assert.Len(t, filters[0].Match.Or, 2)
+		assert.Equal(t, "refund", filters[0].Match.Or[1].Regexp.Pattern)
+	})
+
+	t.Run("Exclude filter negates the patterns", func(t *testing.T) {
+		filters := buildSyslogNGFilters(cappv1alpha1.LogSpec{Filters: &cappv1alpha1.LogFilters{Exclude: []string{"healthz", "readyz"}}})
+		assert.Len(t, filters, 1)
+		assert.NotNil(t, filters[0].Match.Not)
+		assert.Len(t, filters[0].Match.Not.Or, 2)
+	})
+
+	t.Run("Minimum severity matches the severity and above", func(t *testing.T) {
+		filters := buildSyslogNGFilters(cappv1alpha1.LogSpec{Filters: &cappv1alpha1.LogFilters{MinSeverity: severityWarning}})
+		assert.Len(t, filters, 1)
+		pattern := filters[0].Match.Regexp.Pattern
+		assert.Contains(t, pattern, "warn|warning|err|error|crit")
+		assert.NotContains(t, pattern, "info")
+	})
+
+	t.Run("Minimum severity matches the level of the log line", func(t *testing.T) {
+		re := regexp.MustCompile(severityPattern(severityError))
+
+		for _, line := range []string{
+			"ERROR failed to connect to the database",
+			"[error] failed to connect to the database",
+			"  FATAL: out of memory",
+			`{"level":"error","msg":"failed to connect"}`,
+			`{"severity": "CRITICAL", "msg": "out of memory"}`,
+			"time=2024-01-01T00:00:00Z level=error msg=\"failed to connect\"",
+		} {
+			assert.True(t, re.MatchString(line), "Expected %q to match", line)
+		}
+
+		for _, line := range []string{
+			"INFO retrying after error",
+			`{"level":"info","msg":"recovered from a fatal error"}`,
+			"level=warn msg=\"error budget is low\"",
+			"the error count is 0",
+		} {
+			assert.False(t, re.MatchString(line), "Expected %q not to match", line)
+		}
+	})
+
+	t.Run("Parser defaults the prefix and parses the log message", func(t *testing.T) {
+		filters := buildSyslogNGFilters(cappv1alpha1.LogSpec{
+			Filters: &cappv1alpha1.LogFilters{Include: []string{"payment"}},
+			Parser:  &cappv1alpha1.LogParser{Patterns: []string{`"level":"(?<level>[^"]+)"`}},
+		})
+		assert.Len(t, filters, 2)
+		assert.Nil(t, filters[0].Parser, "Expected the filters to be applied before the parser")
+		parser := filters[1].Parser.Regexp
+		assert.Equal(t, defaultParserPrefix, parser.Prefix)
+		assert.Equal(t, "${json#log}", parser.Template)
+		assert.Equal(t, []string{`"level":"(?<level>[^"]+)"`}, parser.Patterns)
+	})
+
+	t.Run("Parser keeps a custom prefix", func(t *testing.T) {
+		filters := buildSyslogNGFilters(cappv1alpha1.LogSpec{Parser: &cappv1alpha1.LogParser{Patterns: []string{"(?<user>\\w+)"}, Prefix: "app."}})
+		assert.Equal(t, "app.", filters[0].Parser.Regexp.Prefix)
+	})
+}
+
+func TestSyslogNGFlowManagerManage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, loggingv1beta1.AddToScheme(scheme))
+
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappSpec{
+			LogSpec: cappv1alpha1.LogSpec{Type: logTypeLoki, Host: "http://loki:3100"},
+			LogDestinations: []cappv1alpha1.LogDestination{
+				{Name: "errors", LogSpec: cappv1alpha1.LogSpec{
+					Type: logTypeSyslog, Host: "syslog.example.com",
+					Filters: &cappv1alpha1.LogFilters{MinSeverity: severityError},
+				}},
+				{Name: "audit", LogSpec: cappv1alpha1.LogSpec{Type: logTypeSyslog, Host: "audit.example.com"}},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	manager := SyslogNGFlowManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+
+	flows := func() map[string]loggingv1beta1.SyslogNGFlow {
+		syslogNGFlows := loggingv1beta1.SyslogNGFlowList{}
+		assert.NoError(t, k8sClient.List(context.Background(), &syslogNGFlows, client.InNamespace("test-ns")))
+
+		flowsByName := map[string]loggingv1beta1.SyslogNGFlow{}
+		for _, syslogNGFlow := range syslogNGFlows.Items {
+			flowsByName[syslogNGFlow.Name] = syslogNGFlow
+		}
+		return flowsByName
+	}
+
+	assert.NoError(t, manager.Manage(capp))
+	syslogNGFlows := flows()
+	assert.Len(t, syslogNGFlows, 3)
+	for _, name := range []string{"test-capp", "test-capp-errors", "test-capp-audit"} {
+		assert.Equal(t, []string{name}, syslogNGFlows[name].Spec.LocalOutputRefs, "Expected each flow to ship to the output of its destination")
+	}
+	assert.Empty(t, syslogNGFlows["test-capp"].Spec.Filters)
+	assert.Len(t, syslogNGFlows["test-capp-errors"].Spec.Filters, 1)
+
+	capp.Spec.LogSpec = cappv1alpha1.LogSpec{}
+	capp.Spec.LogDestinations = capp.Spec.LogDestinations[:1]
+	assert.NoError(t, manager.Manage(capp))
+	assert.Len(t, flows(), 1)
+	assert.Contains(t, flows(), "test-capp-errors", "Expected the flows of removed destinations to be deleted")
+
+	capp.Spec.LogDestinations = nil
+	assert.NoError(t, manager.Manage(capp))
+	assert.Empty(t, flows())
+}
+
+func TestSyslogNGFlowManagerReplacesLegacyFlow(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, loggingv1beta1.AddToScheme(scheme))
+
+	// Before every destination had its own flow, a single flow named after the Capp referenced all of its outputs.
+	legacyFlow := func() *loggingv1beta1.SyslogNGFlow {
+		return &loggingv1beta1.SyslogNGFlow{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-capp",
+				Namespace: "test-ns",
+				Labels:    map[string]string{utils.CappResourceKey: "test-capp", utils.ManagedByLabelKey: utils.CappKey},
+			},
+			Spec: loggingv1beta1.SyslogNGFlowSpec{LocalOutputRefs: []string{"test-capp", "test-capp-security"}},
+		}
+	}
+
+	destinations := []cappv1alpha1.LogDestination{
+		{Name: "security", LogSpec: cappv1alpha1.LogSpec{Type: logTypeSyslog, Host: "syslog.example.com"}},
+	}
+
+	tests := []struct {
+		name          string
+		logSpec       cappv1alpha1.LogSpec
+		expectedFlows map[string][]string
+	}{
+		{
+			name:    "Legacy flow is removed when the Capp has no default destination",
+			logSpec: cappv1alpha1.LogSpec{},
+			expectedFlows: map[string][]string{
+				"test-capp-security": {"test-capp-security"},
+			},
+		},
+		{
+			name:    "Legacy flow is replaced by the flow of the default destination",
+			logSpec: cappv1alpha1.LogSpec{Type: logTypeSyslog, Host: "default.example.com"},
+			expectedFlows: map[string][]string{
+				"test-capp":          {"test-capp"},
+				"test-capp-security": {"test-capp-security"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capp := cappv1alpha1.Capp{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+				Spec:       cappv1alpha1.CappSpec{LogSpec: tt.logSpec, LogDestinations: destinations},
+			}
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacyFlow()).Build()
+			manager := SyslogNGFlowManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+			assert.NoError(t, manager.Manage(capp))
+
+			syslogNGFlows := loggingv1beta1.SyslogNGFlowList{}
+			assert.NoError(t, k8sClient.List(context.Background(), &syslogNGFlows, client.InNamespace("test-ns")))
+
+			outputRefs := map[string][]string{}
+			for _, syslogNGFlow := range syslogNGFlows.Items {
+				outputRefs[syslogNGFlow.Name] = syslogNGFlow.Spec.LocalOutputRefs
+			}
+			assert.Equal(t, tt.expectedFlows, outputRefs)
+		})
+	}
+}
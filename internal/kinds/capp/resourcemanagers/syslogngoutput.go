@@ -3,7 +3,9 @@ package resourcemanagers
 import (
 	"context"
 	"fmt"
-	"reflect"
+	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 
@@ -12,11 +14,13 @@ import (
 	rclient "github.com/dana-team/container-app-operator/internal/kinds/capp/resourceclient"
 	"github.com/go-logr/logr"
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/syslogng/filter"
 	"github.com/kube-logging/logging-operator/pkg/sdk/logging/model/syslogng/output"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 
@@ -27,12 +31,35 @@ const (
 	SyslogNGOutput                        = "syslogNGOutput"
 	eventCappSyslogNGOutputCreationFailed = "SyslogNGOutputCreationFailed"
 	eventCappSyslogNGlSOutputCreated      = "SyslogNGOutputCreated"
+	eventCappSyslogNGOutputIndexInvalid   = "SyslogNGOutputIndexInvalid"
 	logTypeElastic                        = "elastic"
+	logTypeSplunk                         = "splunk"
+	logTypeLoki                           = "loki"
+	logTypeKafka                          = "kafka"
+	logTypeSyslog                         = "syslog"
 	elasticSSLVersion                     = "tlsv1_2"
+	caSecretKey                           = "ca.crt"
+	clientCertSecretKey                   = "tls.crt"
+	clientKeySecretKey                    = "tls.key"
 	elasticTemplate                       = "$(format-json --subkeys json# --key-delimiter #)"
 	elasticSecretKey                      = "elastic"
+	splunkSecretKey                       = "splunk"
+	kafkaSecretKey                        = "kafka"
+	kafkaTopicsPath                       = "/topics/"
+	kafkaContentTypeHeader                = "Content-Type: application/vnd.kafka.json.v2+json"
+	kafkaBodyTemplate                     = `{"records":[{"value":` + elasticTemplate + `}]}`
+	defaultSyslogPort                     = 514
+	defaultSyslogTransport                = "tcp"
+	syslogTransportTLS                    = "tls"
 )
 
+// lokiLabels are the labels of the log streams shipped to Loki, taken from the Kubernetes metadata of the logs.
+var lokiLabels = filter.ArrowMap{
+	"namespace": "${json#kubernetes#namespace_name}",
+	"pod":       "${json#kubernetes#pod_name}",
+	"container": "${json#kubernetes#container_name}",
+}
+
 type SyslogNGOutputManager struct {
 	Ctx           context.Context
 	K8sclient     client.Client
@@ -43,32 +70,24 @@ type SyslogNGOutputManager struct {
 // syslogNGOutputCreators is a map that associates log types with their corresponding SyslogNGOutput creation functions.
 var syslogNGOutputCreators = map[string]func(cappv1alpha1.LogSpec) loggingv1beta1.SyslogNGOutputSpec{
 	logTypeElastic: createElasticsearchOutput,
+	logTypeSplunk:  createSplunkHECOutput,
+	logTypeLoki:    createLokiOutput,
+	logTypeKafka:   createKafkaOutput,
+	logTypeSyslog:  createSyslogOutput,
 }
 
 // createElasticsearchOutput creates an Elasticsearch SyslogNGOutput object based on the provided logSpec.
 // It constructs the Elasticsearch SyslogNGOutput which is returned as a SyslogNGOutputSpec.
 func createElasticsearchOutput(logSpec cappv1alpha1.LogSpec) loggingv1beta1.SyslogNGOutputSpec {
-	peerVerify := false
-
 	syslogNGOutputSpec := loggingv1beta1.SyslogNGOutputSpec{
 		Elasticsearch: &output.ElasticsearchOutput{
 			Index:    logSpec.Index,
 			Template: elasticTemplate,
 			HTTPOutput: output.HTTPOutput{
-				URL:  logSpec.Host,
-				User: logSpec.User,
-				Password: secret.Secret{
-					ValueFrom: &secret.ValueFrom{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{Name: logSpec.PasswordSecret},
-							Key:                  elasticSecretKey,
-						},
-					},
-				},
-				TLS: &output.TLS{
-					PeerVerify: &peerVerify,
-					SslVersion: elasticSSLVersion,
-				},
+				URL:      logSpec.Host,
+				User:     logSpec.User,
+				Password: secretKeyRef(logSpec.PasswordSecret, passwordSecretKey(logSpec, elasticSecretKey)),
+				TLS:      newTLS(logSpec.TLS),
 			},
 		},
 	}
@@ -76,17 +95,168 @@ func createElasticsearchOutput(logSpec cappv1alpha1.LogSpec) loggingv1beta1.Sysl
 	return syslogNGOutputSpec
 }
 
-// prepareResource prepares a SyslogNGOutput resource based on the provided Capp.
-func (o SyslogNGOutputManager) prepareResource(capp cappv1alpha1.Capp) loggingv1beta1.SyslogNGOutput {
-	syslogNGOutputName := capp.GetName()
+// createSplunkHECOutput creates a Splunk HTTP Event Collector SyslogNGOutput object based on the provided logSpec.
+// The HEC token is taken from the password secret.
+func createSplunkHECOutput(logSpec cappv1alpha1.LogSpec) loggingv1beta1.SyslogNGOutputSpec {
+	return loggingv1beta1.SyslogNGOutputSpec{
+		SplunkHEC: &output.SplunkHECOutput{
+			HTTPOutput: output.HTTPOutput{
+				URL: logSpec.Host,
+				TLS: newTLS(logSpec.TLS),
+			},
+			Token: secretKeyRef(logSpec.PasswordSecret, passwordSecretKey(logSpec, splunkSecretKey)),
+			Index: logSpec.Index,
+			Event: elasticTemplate,
+		},
+	}
+}
+
+// createLokiOutput creates a Grafana Loki SyslogNGOutput object based on the provided logSpec.
+// The logs are labeled with the namespace, pod and container they were collected from.
+// The connection uses TLS when the TLS options are set, and is insecure otherwise.
+func createLokiOutput(logSpec cappv1alpha1.LogSpec) loggingv1beta1.SyslogNGOutputSpec {
+	auth := &output.Auth{Insecure: &output.Insecure{}}
+	if logSpec.TLS != (cappv1alpha1.LogTLS{}) {
+		auth = &output.Auth{TLS: newGrpcTLS(logSpec.TLS)}
+	}
+
+	return loggingv1beta1.SyslogNGOutputSpec{
+		Loki: &output.LokiOutput{
+			URL:    logSpec.Host,
+			Labels: lokiLabels,
+			Auth:   auth,
+		},
+	}
+}
+
+// createKafkaOutput creates a SyslogNGOutput object which produces the logs to a Kafka topic
+// through the Kafka REST Proxy, based on the provided logSpec.
+func createKafkaOutput(logSpec cappv1alpha1.LogSpec) loggingv1beta1.SyslogNGOutputSpec {
+	httpOutput := &output.HTTPOutput{
+		URL:     strings.TrimSuffix(logSpec.Host, "/") + kafkaTopicsPath + logSpec.Topic,
+		Method:  http.MethodPost,
+		Headers: []string{kafkaContentTypeHeader},
+		Body:    kafkaBodyTemplate,
+		TLS:     newTLS(logSpec.TLS),
+	}
+
+	if logSpec.User != "" {
+		httpOutput.User = logSpec.User
+		httpOutput.Password = secretKeyRef(logSpec.PasswordSecret, passwordSecretKey(logSpec, kafkaSecretKey))
+	}
+
+	return loggingv1beta1.SyslogNGOutputSpec{HTTP: httpOutput}
+}
+
+// createSyslogOutput creates a syslog SyslogNGOutput object based on the provided logSpec.
+// The port and transport default to 514 and tcp.
+func createSyslogOutput(logSpec cappv1alpha1.LogSpec) loggingv1beta1.SyslogNGOutputSpec {
+	syslogOutput := &output.SyslogOutput{
+		Host:      logSpec.Host,
+		Port:      defaultSyslogPort,
+		Transport: defaultSyslogTransport,
+	}
+
+	if logSpec.Port != 0 {
+		syslogOutput.Port = int(logSpec.Port)
+	}
+
+	if logSpec.Transport != "" {
+		syslogOutput.Transport = logSpec.Transport
+	}
+
+	if syslogOutput.Transport == syslogTransportTLS {
+		syslogOutput.TLS = newTLS(logSpec.TLS)
+	}
+
+	return loggingv1beta1.SyslogNGOutputSpec{Syslog: syslogOutput}
+}
+
+// secretKeyRef returns a secret which is taken from the given key of the secret with the given name.
+func secretKeyRef(name, key string) secret.Secret {
+	return secret.Secret{
+		ValueFrom: &secret.ValueFrom{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// passwordSecretKey returns the key of the password in the password secret of the given logSpec,
+// or the given default key if it is not set.
+func passwordSecretKey(logSpec cappv1alpha1.LogSpec, defaultKey string) string {
+	if logSpec.PasswordSecretKey != "" {
+		return logSpec.PasswordSecretKey
+	}
+	return defaultKey
+}
+
+// newTLS returns the TLS configuration of the outputs based on the provided logTLS.
+// The peer is verified unless disabled, and the TLS version defaults to tlsv1_2.
+func newTLS(logTLS cappv1alpha1.LogTLS) *output.TLS {
+	peerVerify := true
+	if logTLS.PeerVerify != nil {
+		peerVerify = *logTLS.PeerVerify
+	}
+
+	tls := &output.TLS{
+		PeerVerify: &peerVerify,
+		SslVersion: elasticSSLVersion,
+	}
+
+	if logTLS.SSLVersion != "" {
+		tls.SslVersion = logTLS.SSLVersion
+	}
+
+	if logTLS.CASecret != "" {
+		caFile := secretKeyRef(logTLS.CASecret, caSecretKey)
+		tls.CaFile = &caFile
+	}
+
+	if logTLS.ClientCertSecret != "" {
+		certFile := secretKeyRef(logTLS.ClientCertSecret, clientCertSecretKey)
+		keyFile := secretKeyRef(logTLS.ClientCertSecret, clientKeySecretKey)
+		tls.CertFile = &certFile
+		tls.KeyFile = &keyFile
+	}
+
+	return tls
+}
+
+// newGrpcTLS returns the TLS configuration of the gRPC outputs based on the provided logTLS.
+// The peer is always verified, and the TLS version is negotiated.
+func newGrpcTLS(logTLS cappv1alpha1.LogTLS) *output.GrpcTLS {
+	tls := &output.GrpcTLS{}
 
-	if createFunc, ok := syslogNGOutputCreators[capp.Spec.LogSpec.Type]; ok {
-		syslogNGOutputSpec := createFunc(capp.Spec.LogSpec)
+	if logTLS.CASecret != "" {
+		caFile := secretKeyRef(logTLS.CASecret, caSecretKey)
+		tls.CaFile = &caFile
+	}
+
+	if logTLS.ClientCertSecret != "" {
+		certFile := secretKeyRef(logTLS.ClientCertSecret, clientCertSecretKey)
+		keyFile := secretKeyRef(logTLS.ClientCertSecret, clientKeySecretKey)
+		tls.CertFile = &certFile
+		tls.KeyFile = &keyFile
+	}
+
+	return tls
+}
+
+// prepareResource prepares a SyslogNGOutput resource for the given log destination of the provided Capp.
+func (o SyslogNGOutputManager) prepareResource(capp cappv1alpha1.Capp, destination cappv1alpha1.LogDestination) loggingv1beta1.SyslogNGOutput {
+	syslogNGOutputName := utils.GetLogDestinationResourceName(capp.GetName(), destination.Name)
+
+	if createFunc, ok := syslogNGOutputCreators[destination.Type]; ok {
+		syslogNGOutputSpec := createFunc(destination.LogSpec)
 
 		syslogNGOutput := loggingv1beta1.SyslogNGOutput{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      syslogNGOutputName,
-				Namespace: capp.GetNamespace(),
+				Name:            syslogNGOutputName,
+				Namespace:       capp.GetNamespace(),
+				OwnerReferences: ownerReferences(capp),
 				Labels: map[string]string{
 					utils.CappResourceKey:   capp.Name,
 					utils.ManagedByLabelKey: utils.CappKey,
@@ -100,42 +270,103 @@ func (o SyslogNGOutputManager) prepareResource(capp cappv1alpha1.Capp) loggingv1
 	return loggingv1beta1.SyslogNGOutput{}
 }
 
-// CleanUp attempts to delete the associated SyslogNGOutput for a given Capp resource.
+// CleanUp attempts to delete the associated SyslogNGOutputs for a given Capp resource.
 func (o SyslogNGOutputManager) CleanUp(capp cappv1alpha1.Capp) error {
 	resourceManager := rclient.ResourceManagerClient{Ctx: o.Ctx, K8sclient: o.K8sclient, Log: o.Log}
-	syslogNGOutput := rclient.GetBareSyslogNGOutput(capp.Name, capp.Namespace)
 
-	if err := resourceManager.DeleteResource(&syslogNGOutput); err != nil {
-		if errors.IsNotFound(err) {
-			return nil
-		}
+	syslogNGOutputs, err := o.getPreviousSyslogNGOutputs(capp)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return o.deletePreviousSyslogNGOutputs(syslogNGOutputs, resourceManager, []string{})
 }
 
 // IsRequired is responsible to determine if resource logging operator is required.
 func (o SyslogNGOutputManager) IsRequired(capp cappv1alpha1.Capp) bool {
-	return capp.Spec.LogSpec != cappv1alpha1.LogSpec{}
+	return len(utils.GetLogDestinations(capp)) > 0
 }
 
-// Manage creates or updates a SyslogNGOutput resource based on the provided Capp if it's required.
-// If it's not, then it cleans up the resource if it exists.
+// Manage creates or updates a SyslogNGOutput resource for every log destination of the provided Capp
+// if it's required, and deletes the SyslogNGOutputs of removed destinations.
+// If it's not, then it cleans up the resources if they exist.
 func (o SyslogNGOutputManager) Manage(capp cappv1alpha1.Capp) error {
 	if o.IsRequired(capp) {
-		return o.createOrUpdate(capp)
+		return o.create(capp)
 	}
 
 	return o.CleanUp(capp)
 }
 
-// createOrUpdate creates or updates a SyslogNGOutput resource.
-func (o SyslogNGOutputManager) createOrUpdate(capp cappv1alpha1.Capp) error {
-	syslogNGOutputFromCapp := o.prepareResource(capp)
-	syslogNGOutput := loggingv1beta1.SyslogNGOutput{}
+// create creates or updates the SyslogNGOutputs of the log destinations of the Capp,
+// and deletes the SyslogNGOutputs of removed destinations.
+func (o SyslogNGOutputManager) create(capp cappv1alpha1.Capp) error {
 	resourceManager := rclient.ResourceManagerClient{Ctx: o.Ctx, K8sclient: o.K8sclient, Log: o.Log}
 
+	var names []string
+	for _, destination := range utils.GetLogDestinations(capp) {
+		destination, err := o.resolveIndex(capp, destination)
+		if err != nil {
+			o.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappSyslogNGOutputIndexInvalid, err.Error())
+			return err
+		}
+
+		if err := o.createOrUpdate(capp, destination, resourceManager); err != nil {
+			return err
+		}
+		names = append(names, utils.GetLogDestinationResourceName(capp.Name, destination.Name))
+	}
+
+	syslogNGOutputs, err := o.getPreviousSyslogNGOutputs(capp)
+	if err != nil {
+		return err
+	}
+
+	return o.deletePreviousSyslogNGOutputs(syslogNGOutputs, resourceManager, names)
+}
+
+// resolveIndex returns the given log destination with the placeholders of its Elasticsearch index resolved for
+// the Capp. The index defaults to the default index of the CappConfig which applies to the Capp.
+func (o SyslogNGOutputManager) resolveIndex(capp cappv1alpha1.Capp, destination cappv1alpha1.LogDestination) (cappv1alpha1.LogDestination, error) {
+	if destination.Type != logTypeElastic {
+		return destination, nil
+	}
+
+	syslogNGOutputName := utils.GetLogDestinationResourceName(capp.Name, destination.Name)
+
+	index := destination.Index
+	if index == "" {
+		cappConfig, err := utils.ResolveCappConfig(o.Ctx, o.K8sclient, capp.Namespace)
+		if err != nil {
+			return destination, fmt.Errorf("failed to get the default index of SyslogNGOutput %q: %w", syslogNGOutputName, err)
+		}
+		index = cappConfig.Spec.LogConfig.DefaultIndex
+	}
+
+	if index == "" {
+		return destination, fmt.Errorf("SyslogNGOutput %q has no index and the CappConfig has no default index", syslogNGOutputName)
+	}
+
+	namespace := corev1.Namespace{}
+	if err := o.K8sclient.Get(o.Ctx, client.ObjectKey{Name: capp.Namespace}, &namespace); err != nil && !errors.IsNotFound(err) {
+		return destination, fmt.Errorf("failed to get namespace %q: %w", capp.Namespace, err)
+	}
+
+	resolvedIndex, err := utils.ResolveLogIndex(index, utils.NewLogIndexData(capp, namespace.Labels))
+	if err != nil {
+		return destination, fmt.Errorf("failed to resolve the index of SyslogNGOutput %q: %w", syslogNGOutputName, err)
+	}
+
+	destination.Index = resolvedIndex
+	return destination, nil
+}
+
+// createOrUpdate creates or updates the SyslogNGOutput resource of the given log destination. A SyslogNGOutput
+// with the same name which belongs to another Capp is not taken over.
+func (o SyslogNGOutputManager) createOrUpdate(capp cappv1alpha1.Capp, destination cappv1alpha1.LogDestination, resourceManager rclient.ResourceManagerClient) error {
+	syslogNGOutputFromCapp := o.prepareResource(capp, destination)
+	syslogNGOutput := loggingv1beta1.SyslogNGOutput{}
+
 	if err := o.K8sclient.Get(o.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: syslogNGOutputFromCapp.Name}, &syslogNGOutput); err != nil {
 		if errors.IsNotFound(err) {
 			return o.createSyslogNGOutput(syslogNGOutputFromCapp, capp, resourceManager)
@@ -144,12 +375,16 @@ func (o SyslogNGOutputManager) createOrUpdate(capp cappv1alpha1.Capp) error {
 		}
 	}
 
-	return o.updateSyslogNGOutput(&syslogNGOutput, &syslogNGOutputFromCapp, resourceManager)
+	if syslogNGOutput.Labels[utils.CappResourceKey] != capp.Name {
+		return fmt.Errorf("SyslogNGOutput %q already exists and does not belong to Capp %q", syslogNGOutputFromCapp.Name, capp.Name)
+	}
+
+	return applyResource(capp, &syslogNGOutputFromCapp, resourceManager, o.EventRecorder)
 }
 
 // createSyslogNGOutput creates a new SyslogNGOutput and emits an event.
 func (o SyslogNGOutputManager) createSyslogNGOutput(syslogNGOutputFromCapp loggingv1beta1.SyslogNGOutput, capp cappv1alpha1.Capp, resourceManager rclient.ResourceManagerClient) error {
-	if err := resourceManager.CreateResource(&syslogNGOutputFromCapp); err != nil {
+	if _, err := resourceManager.ApplyResource(&syslogNGOutputFromCapp); err != nil {
 		o.EventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappSyslogNGOutputCreationFailed,
 			fmt.Sprintf("Failed to create SyslogNGOutput %s", syslogNGOutputFromCapp.Name))
 		return err
@@ -161,11 +396,33 @@ func (o SyslogNGOutputManager) createSyslogNGOutput(syslogNGOutputFromCapp loggi
 	return nil
 }
 
-// updateSyslogNGOutput checks if an update to the SyslogNGOutput is necessary and performs the update to match desired state.
-func (o SyslogNGOutputManager) updateSyslogNGOutput(syslogNGOutput, syslogNGOutputFromCapp *loggingv1beta1.SyslogNGOutput, resourceManager rclient.ResourceManagerClient) error {
-	if !reflect.DeepEqual(syslogNGOutput.Spec, syslogNGOutputFromCapp.Spec) {
-		syslogNGOutput.Spec = syslogNGOutputFromCapp.Spec
-		return resourceManager.UpdateResource(syslogNGOutput)
+// getPreviousSyslogNGOutputs returns a list of all SyslogNGOutput objects that are related to the given Capp.
+func (o SyslogNGOutputManager) getPreviousSyslogNGOutputs(capp cappv1alpha1.Capp) (loggingv1beta1.SyslogNGOutputList, error) {
+	syslogNGOutputs := loggingv1beta1.SyslogNGOutputList{}
+
+	set := labels.Set{
+		utils.CappResourceKey:   capp.Name,
+		utils.ManagedByLabelKey: utils.CappKey,
+	}
+	listOptions := utils.GetListOptions(set)
+	listOptions.Namespace = capp.Namespace
+
+	if err := o.K8sclient.List(o.Ctx, &syslogNGOutputs, &listOptions); err != nil {
+		return syslogNGOutputs, fmt.Errorf("unable to list SyslogNGOutputs of Capp %q: %w", capp.Name, err)
+	}
+
+	return syslogNGOutputs, nil
+}
+
+// deletePreviousSyslogNGOutputs deletes all previous SyslogNGOutputs associated with a Capp, except for the ones with the given names.
+func (o SyslogNGOutputManager) deletePreviousSyslogNGOutputs(syslogNGOutputs loggingv1beta1.SyslogNGOutputList, resourceManager rclient.ResourceManagerClient, names []string) error {
+	for _, item := range syslogNGOutputs.Items {
+		if !slices.Contains(names, item.Name) {
+			syslogNGOutput := rclient.GetBareSyslogNGOutput(item.Name, item.Namespace)
+			if err := resourceManager.DeleteResource(&syslogNGOutput); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
 	}
 
 	return nil
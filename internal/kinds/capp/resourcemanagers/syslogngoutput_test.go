@@ -0,0 +1,249 @@
+package resourcemanagers
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSyslogNGOutputCreators(t *testing.T) {
+	t.Run("Elasticsearch output keeps the defaults without TLS options", func(t *testing.T) {
+		spec := createElasticsearchOutput(cappv1alpha1.LogSpec{Type: logTypeElastic, Host: "https://elastic:9200", Index: "main", User: "elastic", PasswordSecret: "es-secret"})
+		assert.NotNil(t, spec.Elasticsearch)
+		assert.Equal(t, elasticSecretKey, spec.Elasticsearch.Password.ValueFrom.SecretKeyRef.Key)
+		assert.True(t, *spec.Elasticsearch.TLS.PeerVerify, "Expected the peer to be verified by default")
+		assert.Equal(t, elasticSSLVersion, spec.Elasticsearch.TLS.SslVersion)
+		assert.Nil(t, spec.Elasticsearch.TLS.CaFile)
+		assert.Nil(t, spec.Elasticsearch.TLS.CertFile)
+	})
+
+	t.Run("Elasticsearch output carries the TLS options", func(t *testing.T) {
+		spec := createElasticsearchOutput(cappv1alpha1.LogSpec{
+			Type: logTypeElastic, Host: "https://elastic:9200", Index: "main", User: "elastic",
+			PasswordSecret: "es-secret", PasswordSecretKey: "password",
+			TLS: cappv1alpha1.LogTLS{PeerVerify: ptr.To(false), CASecret: "es-ca", ClientCertSecret: "es-client", SSLVersion: "tlsv1_3"},
+		})
+		tls := spec.Elasticsearch.TLS
+		assert.Equal(t, "password", spec.Elasticsearch.Password.ValueFrom.SecretKeyRef.Key)
+		assert.False(t, *tls.PeerVerify)
+		assert.Equal(t, "tlsv1_3", tls.SslVersion)
+		assert.Equal(t, "es-ca", tls.CaFile.ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, caSecretKey, tls.CaFile.ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, "es-client", tls.CertFile.ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, clientCertSecretKey, tls.CertFile.ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, "es-client", tls.KeyFile.ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, clientKeySecretKey, tls.KeyFile.ValueFrom.SecretKeyRef.Key)
+	})
+
+	t.Run("Splunk HEC output takes the token from the password secret", func(t *testing.T) {
+		spec := createSplunkHECOutput(cappv1alpha1.LogSpec{Type: logTypeSplunk, Host: "https://splunk:8088", Index: "main", PasswordSecret: "hec-token"})
+		assert.NotNil(t, spec.SplunkHEC)
+		assert.Equal(t, "https://splunk:8088", spec.SplunkHEC.URL)
+		assert.Equal(t, "main", spec.SplunkHEC.Index)
+		assert.Equal(t, "hec-token", spec.SplunkHEC.Token.ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, splunkSecretKey, spec.SplunkHEC.Token.ValueFrom.SecretKeyRef.Key)
+	})
+
+	t.Run("Loki output is labeled with the Kubernetes metadata", func(t *testing.T) {
+		spec := createLokiOutput(cappv1alpha1.LogSpec{Type: logTypeLoki, Host: "loki:9095"})
+		assert.NotNil(t, spec.Loki)
+		assert.Equal(t, "loki:9095", spec.Loki.URL)
+		assert.Equal(t, lokiLabels, spec.Loki.Labels)
+		assert.NotNil(t, spec.Loki.Auth.Insecure, "Expected an insecure connection without TLS options")
+	})
+
+	t.Run("Loki output carries the TLS options", func(t *testing.T) {
+		spec := createLokiOutput(cappv1alpha1.LogSpec{
+			Type: logTypeLoki, Host: "loki:9095",
+			TLS: cappv1alpha1.LogTLS{CASecret: "loki-ca", ClientCertSecret: "loki-client"},
+		})
+		assert.Nil(t, spec.Loki.Auth.Insecure)
+		tls := spec.Loki.Auth.TLS
+		assert.Equal(t, "loki-ca", tls.CaFile.ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, caSecretKey, tls.CaFile.ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, "loki-client", tls.CertFile.ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, clientKeySecretKey, tls.KeyFile.ValueFrom.SecretKeyRef.Key)
+	})
+
+	t.Run("Kafka output produces to the topic through the REST proxy", func(t *testing.T) {
+		spec := createKafkaOutput(cappv1alpha1.LogSpec{Type: logTypeKafka, Host: "http://kafka-rest:8082/", Topic: "logs"})
+		assert.NotNil(t, spec.HTTP)
+		assert.Equal(t, "http://kafka-rest:8082/topics/logs", spec.HTTP.URL)
+		assert.Equal(t, []string{kafkaContentTypeHeader}, spec.HTTP.Headers)
+		assert.Nil(t, spec.HTTP.Password.ValueFrom, "Expected no password without a user")
+	})
+
+	t.Run("Syslog output defaults the port and transport", func(t *testing.T) {
+		spec := createSyslogOutput(cappv1alpha1.LogSpec{Type: logTypeSyslog, Host: "syslog.example.com"})
+		assert.NotNil(t, spec.Syslog)
+		assert.Equal(t, defaultSyslogPort, spec.Syslog.Port)
+		assert.Equal(t, defaultSyslogTransport, spec.Syslog.Transport)
+		assert.Nil(t, spec.Syslog.TLS)
+
+		spec = createSyslogOutput(cappv1alpha1.LogSpec{Type: logTypeSyslog, Host: "syslog.example.com", Port: 6514, Transport: syslogTransportTLS})
+		assert.Equal(t, 6514, spec.Syslog.Port)
+		assert.NotNil(t, spec.Syslog.TLS)
+	})
+}
+
+func TestSyslogNGOutputManagerManage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, loggingv1beta1.AddToScheme(scheme))
+
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappSpec{
+			LogSpec: cappv1alpha1.LogSpec{Type: logTypeElastic, Host: "https://elastic:9200", Index: "main", User: "elastic", PasswordSecret: "es-secret"},
+			LogDestinations: []cappv1alpha1.LogDestination{
+				{Name: "security", LogSpec: cappv1alpha1.LogSpec{Type: logTypeSplunk, Host: "https://splunk:8088", Index: "security", PasswordSecret: "hec-token"}},
+				{Name: "audit", LogSpec: cappv1alpha1.LogSpec{Type: logTypeSyslog, Host: "syslog.example.com"}},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	manager := SyslogNGOutputManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+
+	outputNames := func() []string {
+		syslogNGOutputs := loggingv1beta1.SyslogNGOutputList{}
+		assert.NoError(t, k8sClient.List(context.Background(), &syslogNGOutputs, client.InNamespace("test-ns")))
+
+		var names []string
+		for _, syslogNGOutput := range syslogNGOutputs.Items {
+			names = append(names, syslogNGOutput.Name)
+		}
+		return names
+	}
+
+	assert.NoError(t, manager.Manage(capp))
+	assert.ElementsMatch(t, []string{"test-capp", "test-capp-security", "test-capp-audit"}, outputNames())
+
+	capp.Spec.LogSpec = cappv1alpha1.LogSpec{}
+	capp.Spec.LogDestinations = capp.Spec.LogDestinations[:1]
+	assert.NoError(t, manager.Manage(capp))
+	assert.ElementsMatch(t, []string{"test-capp-security"}, outputNames(), "Expected the outputs of removed destinations to be deleted")
+
+	capp.Spec.LogDestinations = nil
+	assert.NoError(t, manager.Manage(capp))
+	assert.Empty(t, outputNames())
+}
+
+func TestSyslogNGOutputManagerResolveIndex(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+
+	cappConfig := &cappv1alpha1.CappConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.CappConfigName, Namespace: utils.CappNS},
+		Spec:       cappv1alpha1.CappConfigSpec{LogConfig: cappv1alpha1.LogConfig{DefaultIndex: "{{ .Namespace }}-{{ .Date }}"}},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns", Labels: map[string]string{"team": "payments"}}}
+	capp := cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"}}
+	elastic := cappv1alpha1.LogSpec{Type: logTypeElastic, Host: "https://elastic:9200", User: "elastic", PasswordSecret: "es-secret"}
+
+	tests := []struct {
+		name          string
+		objects       []client.Object
+		index         string
+		expected      string
+		errorContains string
+	}{
+		{
+			name:     "Templated index of the Capp",
+			objects:  []client.Object{cappConfig, namespace},
+			index:    "{{ .NamespaceLabels.team }}-{{ .CappName }}-{{ .Date }}",
+			expected: "payments-test-capp-${YEAR}.${MONTH}.${DAY}",
+		},
+		{
+			name:     "Default index of the CappConfig",
+			objects:  []client.Object{cappConfig, namespace},
+			expected: "test-ns-${YEAR}.${MONTH}.${DAY}",
+		},
+		{
+			name:          "No index and no CappConfig",
+			objects:       []client.Object{namespace},
+			errorContains: "failed to get the default index",
+		},
+		{
+			name:          "Missing namespace label",
+			objects:       []client.Object{cappConfig, namespace},
+			index:         "{{ .NamespaceLabels.tenant }}",
+			errorContains: "failed to resolve the index",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			manager := SyslogNGOutputManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+
+			logSpec := elastic
+			logSpec.Index = tt.index
+			destination, err := manager.resolveIndex(capp, cappv1alpha1.LogDestination{LogSpec: logSpec})
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, destination.Index)
+			assert.Equal(t, tt.expected, manager.prepareResource(capp, destination).Spec.Elasticsearch.Index)
+		})
+	}
+
+	t.Run("Index of other log types is not resolved", func(t *testing.T) {
+		manager := SyslogNGOutputManager{Ctx: context.Background(), K8sclient: fake.NewClientBuilder().WithScheme(scheme).Build()}
+		splunk := cappv1alpha1.LogDestination{LogSpec: cappv1alpha1.LogSpec{Type: logTypeSplunk, Index: "{{ .Namespace }}"}}
+		destination, err := manager.resolveIndex(capp, splunk)
+		assert.NoError(t, err)
+		assert.Equal(t, splunk, destination)
+	})
+}
+
+func TestSyslogNGOutputManagerNameCollision(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, loggingv1beta1.AddToScheme(scheme))
+
+	otherOutput := &loggingv1beta1.SyslogNGOutput{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-logs",
+			Namespace: "test-ns",
+			Labels:    map[string]string{utils.CappResourceKey: "web-logs", utils.ManagedByLabelKey: utils.CappKey},
+		},
+		Spec: loggingv1beta1.SyslogNGOutputSpec{Loki: createLokiOutput(cappv1alpha1.LogSpec{Host: "loki:9095"}).Loki},
+	}
+
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappSpec{
+			LogDestinations: []cappv1alpha1.LogDestination{
+				{Name: "logs", LogSpec: cappv1alpha1.LogSpec{Type: logTypeSyslog, Host: "syslog.example.com"}},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(otherOutput).Build()
+	manager := SyslogNGOutputManager{Ctx: context.Background(), K8sclient: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+
+	err := manager.Manage(capp)
+	assert.ErrorContains(t, err, "does not belong to Capp")
+
+	syslogNGOutput := loggingv1beta1.SyslogNGOutput{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(otherOutput), &syslogNGOutput))
+	assert.Equal(t, "web-logs", syslogNGOutput.Labels[utils.CappResourceKey])
+	assert.NotNil(t, syslogNGOutput.Spec.Loki, "Expected the output of the other Capp to be left untouched")
+}
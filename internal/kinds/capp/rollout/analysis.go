@@ -0,0 +1,230 @@
+package rollout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+)
+
+const (
+	queryPath           = "/api/v1/query"
+	queryTimeout        = 10 * time.Second
+	resultTypeVector    = "vector"
+	resultTypeScalar    = "scalar"
+	prometheusStatusOK  = "success"
+	maxErrorBodyPreview = 512
+)
+
+// MetricsProvider runs queries against a Prometheus-compatible endpoint.
+type MetricsProvider interface {
+	// Query runs an instant query against the endpoint at the given address and returns its single value.
+	Query(ctx context.Context, address, query string) (float64, error)
+}
+
+// PrometheusProvider is a MetricsProvider which uses the Prometheus HTTP API.
+type PrometheusProvider struct {
+	Client *http.Client
+}
+
+// NewPrometheusProvider returns a PrometheusProvider with a default HTTP client.
+func NewPrometheusProvider() PrometheusProvider {
+	return PrometheusProvider{Client: &http.Client{Timeout: queryTimeout}}
+}
+
+// prometheusResponse is the response of the Prometheus instant query API.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// vectorSample is a single sample of an instant vector.
+type vectorSample struct {
+	Value []interface{} `json:"value"`
+}
+
+// Query runs an instant query against the Prometheus HTTP API. The query must return a scalar
+// or a vector with exactly one sample.
+func (p PrometheusProvider) Query(ctx context.Context, address, query string) (float64, error) {
+	endpoint, err := url.JoinPath(address, queryPath)
+	if err != nil {
+		return 0, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+url.Values{"query": {query}}.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := p.Client.Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %q: %w", address, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the response of %q: %w", address, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("query failed with status %d: %s", response.StatusCode, previewBody(body))
+	}
+
+	return parsePrometheusResponse(body)
+}
+
+// parsePrometheusResponse returns the single value of a Prometheus instant query response.
+func parsePrometheusResponse(body []byte) (float64, error) {
+	promResponse := prometheusResponse{}
+	if err := json.Unmarshal(body, &promResponse); err != nil {
+		return 0, fmt.Errorf("failed to parse the query response: %w", err)
+	}
+
+	if promResponse.Status != prometheusStatusOK {
+		return 0, fmt.Errorf("query failed: %s", promResponse.Error)
+	}
+
+	var sampleValue []interface{}
+	switch promResponse.Data.ResultType {
+	case resultTypeScalar:
+		if err := json.Unmarshal(promResponse.Data.Result, &sampleValue); err != nil {
+			return 0, fmt.Errorf("failed to parse the scalar result: %w", err)
+		}
+	case resultTypeVector:
+		var samples []vectorSample
+		if err := json.Unmarshal(promResponse.Data.Result, &samples); err != nil {
+			return 0, fmt.Errorf("failed to parse the vector result: %w", err)
+		}
+		if len(samples) != 1 {
+			return 0, fmt.Errorf("query returned %d samples instead of 1", len(samples))
+		}
+		sampleValue = samples[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %q", promResponse.Data.ResultType)
+	}
+
+	if len(sampleValue) != 2 {
+		return 0, fmt.Errorf("malformed sample %v", sampleValue)
+	}
+
+	value, ok := sampleValue[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("malformed sample value %v", sampleValue[1])
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+// previewBody returns the beginning of a response body to be used in error messages.
+func previewBody(body []byte) string {
+	preview := strings.TrimSpace(string(body))
+	if len(preview) > maxErrorBodyPreview {
+		return preview[:maxErrorBodyPreview]
+	}
+	return preview
+}
+
+// analysisTemplateData is the data available to the query templates of a rollout analysis.
+type analysisTemplateData struct {
+	Revision       string
+	StableRevision string
+	Namespace      string
+	CappName       string
+}
+
+// analysisResult is the outcome of a rollout analysis.
+type analysisResult struct {
+	Passed  bool
+	Message string
+}
+
+// evaluateAnalysis runs every metric of the analysis and checks its value is within the thresholds.
+// An error is returned if a metric could not be evaluated, in which case the analysis is inconclusive.
+func evaluateAnalysis(ctx context.Context, provider MetricsProvider, analysis cappv1alpha1.RolloutAnalysis, data analysisTemplateData) (analysisResult, error) {
+	for _, metric := range analysis.Metrics {
+		query, err := renderQuery(metric.Query, data)
+		if err != nil {
+			return analysisResult{}, fmt.Errorf("metric %q: %w", metric.Name, err)
+		}
+
+		value, err := provider.Query(ctx, analysis.Address, query)
+		if err != nil {
+			return analysisResult{}, fmt.Errorf("metric %q: %w", metric.Name, err)
+		}
+
+		passed, err := isWithinThresholds(value, metric.Min, metric.Max)
+		if err != nil {
+			return analysisResult{}, fmt.Errorf("metric %q: %w", metric.Name, err)
+		}
+
+		if !passed {
+			return analysisResult{
+				Message: fmt.Sprintf("metric %q value %v is outside of the range [%s, %s]", metric.Name, value, thresholdOrAny(metric.Min), thresholdOrAny(metric.Max)),
+			}, nil
+		}
+	}
+
+	return analysisResult{Passed: true}, nil
+}
+
+// renderQuery renders the query template of a metric.
+func renderQuery(query string, data analysisTemplateData) (string, error) {
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render query: %w", err)
+	}
+
+	return rendered.String(), nil
+}
+
+// isWithinThresholds checks whether the value is within the given minimum and maximum. An empty
+// threshold is not enforced.
+func isWithinThresholds(value float64, minimum, maximum string) (bool, error) {
+	if minimum != "" {
+		minValue, err := strconv.ParseFloat(minimum, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid min %q: %w", minimum, err)
+		}
+		if value < minValue {
+			return false, nil
+		}
+	}
+
+	if maximum != "" {
+		maxValue, err := strconv.ParseFloat(maximum, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid max %q: %w", maximum, err)
+		}
+		if value > maxValue {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func thresholdOrAny(threshold string) string {
+	if threshold == "" {
+		return "*"
+	}
+	return threshold
+}
@@ -0,0 +1,216 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	PhaseProgressing = "Progressing"
+	PhaseSucceeded   = "Succeeded"
+	PhaseFailed      = "Failed"
+
+	// CanaryTag is the tag of the canary revision in the routing table of the Capp route.
+	CanaryTag = "canary"
+
+	eventRolloutStarted       = "RolloutStarted"
+	eventRolloutStepPromoted  = "RolloutStepPromoted"
+	eventRolloutSucceeded     = "RolloutSucceeded"
+	eventRolloutRolledBack    = "RolloutRolledBack"
+	eventRolloutAnalysisError = "RolloutAnalysisError"
+
+	// analysisRetryInterval is how long to wait before the analysis is evaluated again after it could not be completed.
+	analysisRetryInterval = 30 * time.Second
+)
+
+// Manager drives the rollout of new revisions of a Capp according to its rollout strategy.
+type Manager struct {
+	Ctx             context.Context
+	K8sclient       client.Client
+	Log             logr.Logger
+	EventRecorder   record.EventRecorder
+	MetricsProvider MetricsProvider
+}
+
+// Sync advances the rollout of the Capp and sets its rollout status. It returns how long to wait before the
+// rollout should be synced again, or zero if no rollout is in progress.
+func (m Manager) Sync(capp *cappv1alpha1.Capp) (time.Duration, error) {
+	if capp.Spec.Rollout == nil {
+		capp.Status.Rollout = nil
+		return 0, nil
+	}
+
+	knativeService := knativev1.Service{}
+	if err := m.K8sclient.Get(m.Ctx, types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name}, &knativeService); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get KnativeService %q: %w", capp.Name, err)
+	}
+
+	latestReadyRevision := knativeService.Status.LatestReadyRevisionName
+	if latestReadyRevision == "" {
+		return 0, nil
+	}
+
+	if capp.Status.Rollout == nil || capp.Status.Rollout.StableRevision == "" {
+		// the first ready revision has nothing to be compared with, so it is promoted directly
+		capp.Status.Rollout = &cappv1alpha1.RolloutStatus{
+			Phase:          PhaseSucceeded,
+			StableRevision: latestReadyRevision,
+			Message:        fmt.Sprintf("Revision %q is stable", latestReadyRevision),
+		}
+		return 0, nil
+	}
+
+	status := capp.Status.Rollout
+	if latestReadyRevision != status.StableRevision && latestReadyRevision != status.CanaryRevision {
+		m.startRollout(capp, latestReadyRevision)
+	}
+
+	if status.Phase != PhaseProgressing {
+		return 0, nil
+	}
+
+	return m.progressRollout(capp)
+}
+
+// startRollout starts the rollout of the given revision from the first step.
+func (m Manager) startRollout(capp *cappv1alpha1.Capp, revision string) {
+	status := capp.Status.Rollout
+	status.Phase = PhaseProgressing
+	status.CanaryRevision = revision
+	status.CurrentStep = 0
+	status.CanaryPercent = capp.Spec.Rollout.Steps[0].Percent
+	status.StepStartTime = metav1.Now()
+	status.Message = fmt.Sprintf("Rolling out revision %q at step 1 with %d%% of the traffic", revision, status.CanaryPercent)
+
+	m.EventRecorder.Event(capp, corev1.EventTypeNormal, eventRolloutStarted, status.Message)
+}
+
+// progressRollout waits for the pause of the current step to end, evaluates the analysis and then either
+// promotes the canary revision to the next step or rolls it back.
+func (m Manager) progressRollout(capp *cappv1alpha1.Capp) (time.Duration, error) {
+	status := capp.Status.Rollout
+	steps := capp.Spec.Rollout.Steps
+	if status.CurrentStep >= len(steps) {
+		status.CurrentStep = len(steps) - 1
+	}
+
+	if remaining := stepRemainingPause(steps[status.CurrentStep], status.StepStartTime.Time, time.Now()); remaining > 0 {
+		return remaining, nil
+	}
+
+	if analysis := capp.Spec.Rollout.Analysis; analysis != nil {
+		result, err := evaluateAnalysis(m.Ctx, m.MetricsProvider, *analysis, analysisTemplateData{
+			Revision:       status.CanaryRevision,
+			StableRevision: status.StableRevision,
+			Namespace:      capp.Namespace,
+			CappName:       capp.Name,
+		})
+		if err != nil {
+			status.Message = fmt.Sprintf("Analysis of revision %q could not be completed: %s", status.CanaryRevision, err.Error())
+			m.EventRecorder.Event(capp, corev1.EventTypeWarning, eventRolloutAnalysisError, status.Message)
+			return analysisRetryInterval, nil
+		}
+
+		if !result.Passed {
+			m.rollBack(capp, result.Message)
+			return 0, nil
+		}
+	}
+
+	status.CurrentStep++
+	if status.CurrentStep >= len(steps) {
+		m.promote(capp)
+		return 0, nil
+	}
+
+	status.CanaryPercent = steps[status.CurrentStep].Percent
+	status.StepStartTime = metav1.Now()
+	status.Message = fmt.Sprintf("Rolling out revision %q at step %d with %d%% of the traffic", status.CanaryRevision, status.CurrentStep+1, status.CanaryPercent)
+	m.EventRecorder.Event(capp, corev1.EventTypeNormal, eventRolloutStepPromoted, status.Message)
+
+	return stepRemainingPause(steps[status.CurrentStep], status.StepStartTime.Time, time.Now()), nil
+}
+
+// promote makes the canary revision the stable revision, routing all the traffic to it.
+func (m Manager) promote(capp *cappv1alpha1.Capp) {
+	status := capp.Status.Rollout
+	status.Phase = PhaseSucceeded
+	status.StableRevision = status.CanaryRevision
+	status.CanaryPercent = 0
+	status.Message = fmt.Sprintf("Revision %q was promoted and is stable", status.CanaryRevision)
+
+	m.EventRecorder.Event(capp, corev1.EventTypeNormal, eventRolloutSucceeded, status.Message)
+}
+
+// rollBack returns all the traffic to the stable revision.
+func (m Manager) rollBack(capp *cappv1alpha1.Capp, reason string) {
+	status := capp.Status.Rollout
+	status.Phase = PhaseFailed
+	status.CanaryPercent = 0
+	status.Message = fmt.Sprintf("Revision %q was rolled back to %q: %s", status.CanaryRevision, status.StableRevision, reason)
+
+	m.EventRecorder.Event(capp, corev1.EventTypeWarning, eventRolloutRolledBack, status.Message)
+}
+
+// stepRemainingPause returns how long is left of the pause of a step which began at the given time.
+func stepRemainingPause(step cappv1alpha1.RolloutStep, stepStartTime, now time.Time) time.Duration {
+	if step.Pause == nil {
+		return 0
+	}
+
+	remaining := stepStartTime.Add(step.Pause.Duration).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+// GetTrafficTargets returns the routing table of the Capp route. When a rollout strategy is set and a stable
+// revision is known, the traffic is pinned to the stable revision and to the canary revision, if one is being
+// rolled out, so that new revisions never receive traffic before the rollout begins. The canary revision is
+// tagged, so that it is exposed on a preview URL while it receives no traffic.
+func GetTrafficTargets(capp cappv1alpha1.Capp) []knativev1.TrafficTarget {
+	status := capp.Status.Rollout
+	if capp.Spec.Rollout == nil || status == nil || status.StableRevision == "" {
+		return utils.GetTrafficTargets(capp.Spec.RouteSpec)
+	}
+
+	var canaryPercent int64
+	if status.Phase == PhaseProgressing && status.CanaryRevision != "" {
+		canaryPercent = status.CanaryPercent
+	}
+
+	trafficTargets := []knativev1.TrafficTarget{
+		{
+			RevisionName: status.StableRevision,
+			Percent:      ptr.To(100 - canaryPercent),
+		},
+	}
+
+	if status.Phase == PhaseProgressing && status.CanaryRevision != "" {
+		trafficTargets = append(trafficTargets, knativev1.TrafficTarget{
+			Tag:          CanaryTag,
+			RevisionName: status.CanaryRevision,
+			Percent:      ptr.To(canaryPercent),
+		})
+	}
+
+	return trafficTargets
+}
@@ -0,0 +1,250 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "test-ns"
+	testCappName  = "test-capp"
+)
+
+type fakeMetricsProvider struct {
+	value float64
+	err   error
+}
+
+func (f fakeMetricsProvider) Query(_ context.Context, _, _ string) (float64, error) {
+	return f.value, f.err
+}
+
+func newTestManager(t *testing.T, latestReadyRevision string, provider MetricsProvider) Manager {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, knativev1.AddToScheme(scheme))
+
+	knativeService := &knativev1.Service{ObjectMeta: metav1.ObjectMeta{Name: testCappName, Namespace: testNamespace}}
+	knativeService.Status.LatestReadyRevisionName = latestReadyRevision
+
+	return Manager{
+		Ctx:             context.Background(),
+		K8sclient:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(knativeService).Build(),
+		Log:             logr.Discard(),
+		EventRecorder:   record.NewFakeRecorder(10),
+		MetricsProvider: provider,
+	}
+}
+
+func newTestCapp(status *cappv1alpha1.RolloutStatus) cappv1alpha1.Capp {
+	return cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: testCappName, Namespace: testNamespace},
+		Spec: cappv1alpha1.CappSpec{
+			Rollout: &cappv1alpha1.RolloutSpec{
+				Steps: []cappv1alpha1.RolloutStep{
+					{Percent: 10, Pause: &metav1.Duration{Duration: time.Minute}},
+					{Percent: 30},
+					{Percent: 100},
+				},
+				Analysis: &cappv1alpha1.RolloutAnalysis{
+					Address: "http://prometheus:9090",
+					Metrics: []cappv1alpha1.RolloutMetric{{Name: "success-rate", Query: "up", Min: "0.99"}},
+				},
+			},
+		},
+		Status: cappv1alpha1.CappStatus{Rollout: status},
+	}
+}
+
+func TestSync(t *testing.T) {
+	elapsed := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+
+	tests := []struct {
+		name                string
+		latestReadyRevision string
+		status              *cappv1alpha1.RolloutStatus
+		provider            MetricsProvider
+		expectedPhase       string
+		expectedStable      string
+		expectedStep        int
+		expectedPercent     int64
+		expectRequeue       bool
+	}{
+		{
+			name:                "First revision is promoted directly",
+			latestReadyRevision: "app-00001",
+			expectedPhase:       PhaseSucceeded,
+			expectedStable:      "app-00001",
+		},
+		{
+			name:                "New revision starts a rollout",
+			latestReadyRevision: "app-00002",
+			status:              &cappv1alpha1.RolloutStatus{Phase: PhaseSucceeded, StableRevision: "app-00001"},
+			expectedPhase:       PhaseProgressing,
+			expectedStable:      "app-00001",
+			expectedPercent:     10,
+			expectRequeue:       true,
+		},
+		{
+			name:                "Passing analysis promotes the next step",
+			latestReadyRevision: "app-00002",
+			status: &cappv1alpha1.RolloutStatus{
+				Phase: PhaseProgressing, StableRevision: "app-00001", CanaryRevision: "app-00002", CanaryPercent: 10, StepStartTime: elapsed,
+			},
+			provider:        fakeMetricsProvider{value: 1},
+			expectedPhase:   PhaseProgressing,
+			expectedStable:  "app-00001",
+			expectedStep:    1,
+			expectedPercent: 30,
+		},
+		{
+			name:                "Passing analysis of the last step promotes the revision",
+			latestReadyRevision: "app-00002",
+			status: &cappv1alpha1.RolloutStatus{
+				Phase: PhaseProgressing, StableRevision: "app-00001", CanaryRevision: "app-00002", CurrentStep: 2, CanaryPercent: 100, StepStartTime: elapsed,
+			},
+			provider:       fakeMetricsProvider{value: 1},
+			expectedPhase:  PhaseSucceeded,
+			expectedStable: "app-00002",
+			expectedStep:   3,
+		},
+		{
+			name:                "Failing analysis rolls back",
+			latestReadyRevision: "app-00002",
+			status: &cappv1alpha1.RolloutStatus{
+				Phase: PhaseProgressing, StableRevision: "app-00001", CanaryRevision: "app-00002", CanaryPercent: 10, StepStartTime: elapsed,
+			},
+			provider:       fakeMetricsProvider{value: 0.5},
+			expectedPhase:  PhaseFailed,
+			expectedStable: "app-00001",
+		},
+		{
+			name:                "Inconclusive analysis is retried",
+			latestReadyRevision: "app-00002",
+			status: &cappv1alpha1.RolloutStatus{
+				Phase: PhaseProgressing, StableRevision: "app-00001", CanaryRevision: "app-00002", CanaryPercent: 10, StepStartTime: elapsed,
+			},
+			provider:        fakeMetricsProvider{err: errors.New("connection refused")},
+			expectedPhase:   PhaseProgressing,
+			expectedStable:  "app-00001",
+			expectedPercent: 10,
+			expectRequeue:   true,
+		},
+		{
+			name:                "Rolled back revision is not retried",
+			latestReadyRevision: "app-00002",
+			status:              &cappv1alpha1.RolloutStatus{Phase: PhaseFailed, StableRevision: "app-00001", CanaryRevision: "app-00002"},
+			expectedPhase:       PhaseFailed,
+			expectedStable:      "app-00001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newTestManager(t, tt.latestReadyRevision, tt.provider)
+			capp := newTestCapp(tt.status)
+
+			requeueAfter, err := manager.Sync(&capp)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectRequeue, requeueAfter > 0)
+			assert.Equal(t, tt.expectedPhase, capp.Status.Rollout.Phase)
+			assert.Equal(t, tt.expectedStable, capp.Status.Rollout.StableRevision)
+			assert.Equal(t, tt.expectedStep, capp.Status.Rollout.CurrentStep)
+			assert.Equal(t, tt.expectedPercent, capp.Status.Rollout.CanaryPercent)
+		})
+	}
+}
+
+func TestGetTrafficTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   *cappv1alpha1.RolloutStatus
+		expected []knativev1.TrafficTarget
+	}{
+		{
+			name: "No stable revision yet",
+		},
+		{
+			name:   "Stable revision only",
+			status: &cappv1alpha1.RolloutStatus{Phase: PhaseFailed, StableRevision: "app-00001", CanaryRevision: "app-00002"},
+			expected: []knativev1.TrafficTarget{
+				{RevisionName: "app-00001", Percent: ptr.To(int64(100))},
+			},
+		},
+		{
+			name:   "Canary in progress",
+			status: &cappv1alpha1.RolloutStatus{Phase: PhaseProgressing, StableRevision: "app-00001", CanaryRevision: "app-00002", CanaryPercent: 30},
+			expected: []knativev1.TrafficTarget{
+				{RevisionName: "app-00001", Percent: ptr.To(int64(70))},
+				{Tag: CanaryTag, RevisionName: "app-00002", Percent: ptr.To(int64(30))},
+			},
+		},
+		{
+			name:   "Canary without traffic is tagged for preview",
+			status: &cappv1alpha1.RolloutStatus{Phase: PhaseProgressing, StableRevision: "app-00001", CanaryRevision: "app-00002"},
+			expected: []knativev1.TrafficTarget{
+				{RevisionName: "app-00001", Percent: ptr.To(int64(100))},
+				{Tag: CanaryTag, RevisionName: "app-00002", Percent: ptr.To(int64(0))},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GetTrafficTargets(newTestCapp(tt.status)))
+		})
+	}
+}
+
+func TestParsePrometheusResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		expected    float64
+		expectError bool
+	}{
+		{
+			name:     "Vector with a single sample",
+			body:     `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.995"]}]}}`,
+			expected: 0.995,
+		},
+		{
+			name:     "Scalar",
+			body:     `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"42"]}}`,
+			expected: 42,
+		},
+		{
+			name:        "Empty vector",
+			body:        `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			expectError: true,
+		},
+		{
+			name:        "Query error",
+			body:        `{"status":"error","error":"bad query"}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := parsePrometheusResponse([]byte(tt.body))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package status
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
 
@@ -23,32 +25,39 @@ func CreateStateStatus(stateStatus *cappv1alpha1.StateStatus, cappStateFromSpec
 
 // SyncStatus is the main function that synchronizes the status of the Capp CRD with the Knative service and revisions associated with it.
 // It gets the Capp CRD, builds the ApplicationLinks and RevisionInfo statuses, and updates the status of the Capp CRD if it has changed.
-func SyncStatus(ctx context.Context, capp cappv1alpha1.Capp, log logr.Logger, r client.Client, onOpenshift bool, resourceManagers map[string]rmanagers.ResourceManager) error {
+// A part of the status which fails to be built keeps its previous value, and the status is still updated. The given failures
+// of the resource managers, keyed by the manager name, are reported in the conditions of the matching subsystems.
+func SyncStatus(ctx context.Context, capp cappv1alpha1.Capp, log logr.Logger, r client.Client, onOpenshift bool, resourceManagers map[string]rmanagers.ResourceManager, failures map[string]error) error {
 	cappObject := cappv1alpha1.Capp{}
 	if err := r.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name}, &cappObject); err != nil {
 		return err
 	}
 
+	var errs []error
+
 	applicationLinks, err := buildApplicationLinks(ctx, log, r, onOpenshift)
 	if err != nil {
-		return err
+		errs = append(errs, fmt.Errorf("failed to build application links status: %w", err))
+	} else {
+		cappObject.Status.ApplicationLinks = *applicationLinks
 	}
 
 	knativeServiceManager := resourceManagers[rmanagers.KnativeServing]
 	knativeObjectStatus, revisionInfo, err := buildKnativeStatus(ctx, r, capp, knativeServiceManager.IsRequired(capp))
 	if err != nil {
-		return err
+		errs = append(errs, fmt.Errorf("failed to build Knative status: %w", err))
+	} else {
+		cappObject.Status.KnativeObjectStatus = knativeObjectStatus
+		cappObject.Status.RevisionInfo = revisionInfo
 	}
 
-	cappObject.Status.KnativeObjectStatus = knativeObjectStatus
-	cappObject.Status.RevisionInfo = revisionInfo
-
 	syslogNGFlowManager := resourceManagers[rmanagers.SyslogNGFlow]
 	loggingStatus, err := buildLoggingStatus(ctx, capp, log, r, syslogNGFlowManager.IsRequired(capp))
 	if err != nil {
-		return err
+		errs = append(errs, fmt.Errorf("failed to build logging status: %w", err))
+	} else {
+		cappObject.Status.LoggingStatus = loggingStatus
 	}
-	cappObject.Status.LoggingStatus = loggingStatus
 
 	routeRequired := map[string]bool{
 		rmanagers.DomainMapping: resourceManagers[rmanagers.DomainMapping].IsRequired(capp),
@@ -57,26 +66,43 @@ func SyncStatus(ctx context.Context, capp cappv1alpha1.Capp, log logr.Logger, r
 	}
 	routeStatus, err := buildRouteStatus(ctx, r, capp, routeRequired)
 	if err != nil {
-		return err
+		errs = append(errs, fmt.Errorf("failed to build route status: %w", err))
+	} else {
+		cappObject.Status.RouteStatus = routeStatus
 	}
-	cappObject.Status.RouteStatus = routeStatus
 
 	nfspvcManager := resourceManagers[rmanagers.NfsPVC]
 	volumesStatus, err := buildVolumesStatus(ctx, r, capp, nfspvcManager.IsRequired(capp))
 	if err != nil {
-		return err
+		errs = append(errs, fmt.Errorf("failed to build volumes status: %w", err))
+	} else {
+		cappObject.Status.VolumesStatus = volumesStatus
 	}
-	cappObject.Status.VolumesStatus = volumesStatus
 
+	kedaSourceManager := resourceManagers[rmanagers.KedaSource]
+	sourcesStatus, err := buildSourcesStatus(ctx, r, capp, kedaSourceManager.IsRequired(capp))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to build sources status: %w", err))
+	} else {
+		cappObject.Status.SourceStatus = sourcesStatus
+	}
+
+	cappObject.Status.Rollout = capp.Status.Rollout
+	cappObject.Status.BlockedResources = capp.Status.BlockedResources
+	cappObject.Status.CappConfigGenerations = capp.Status.CappConfigGenerations
 	CreateStateStatus(&cappObject.Status.StateStatus, capp.Spec.State)
-	cappObject.Status.KnativeObjectStatus = knativeObjectStatus
-	cappObject.Status.RevisionInfo = revisionInfo
-	cappObject.Status.ApplicationLinks = *applicationLinks
+
+	isRequired := map[string]bool{}
+	for name, manager := range resourceManagers {
+		isRequired[name] = manager.IsRequired(capp)
+	}
+	cappObject.Status.Conditions = buildConditions(capp, cappObject.Status, isRequired, failures)
+	cappObject.Status.ObservedGeneration = capp.Generation
 
 	if err := r.Status().Update(ctx, &cappObject); err != nil {
 		log.Error(err, "failed to update Capp status")
-		return err
+		return errors.Join(append(errs, err)...)
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
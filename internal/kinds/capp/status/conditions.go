@@ -0,0 +1,335 @@
+package status
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	ConditionTypeReady               = "Ready"
+	ConditionTypeKnativeServiceReady = "KnativeServiceReady"
+	ConditionTypeRouteReady          = "RouteReady"
+	ConditionTypeDNSReady            = "DNSReady"
+	ConditionTypeCertificateReady    = "CertificateReady"
+	ConditionTypeLoggingReady        = "LoggingReady"
+	ConditionTypeVolumesReady        = "VolumesReady"
+	ConditionTypeSourcesReady        = "SourcesReady"
+
+	ReasonReady       = "Ready"
+	ReasonNotReady    = "NotReady"
+	ReasonInProgress  = "InProgress"
+	ReasonNotRequired = "NotRequired"
+	ReasonDisabled    = "Disabled"
+	ReasonBlocked     = "Blocked"
+
+	ReasonReconcileFailed = "ReconcileFailed"
+
+	nfsPVCBoundPhase = "Bound"
+)
+
+// objectReadiness is the readiness of a single object which a subsystem condition is built from.
+type objectReadiness struct {
+	name    string
+	status  metav1.ConditionStatus
+	message string
+}
+
+// buildConditions computes the conditions of every subsystem of the Capp from the given status, and an
+// aggregated Ready condition which is True only when all of them are True. The condition of a subsystem
+// whose resource manager is in the given failures is False. Conditions whose status has not changed since
+// they were last set in the given status keep their LastTransitionTime.
+func buildConditions(capp cappv1alpha1.Capp, cappStatus cappv1alpha1.CappStatus, isRequired map[string]bool, failures map[string]error) []metav1.Condition {
+	conditions := make([]metav1.Condition, len(cappStatus.Conditions))
+	copy(conditions, cappStatus.Conditions)
+
+	blocked := cappStatus.BlockedResources
+	subsystemConditions := []metav1.Condition{
+		withFailures(buildKnativeServiceCondition(capp, cappStatus, isRequired[rmanagers.KnativeServing]), failures, rmanagers.KnativeServing),
+		withFailures(withBlocked(buildRouteCondition(cappStatus, isRequired[rmanagers.DomainMapping]), blocked, rmanagers.DomainMapping), failures, rmanagers.DomainMapping),
+		withFailures(withBlocked(buildDNSCondition(cappStatus, isRequired[rmanagers.DNSRecord]), blocked, rmanagers.DNSRecord), failures, rmanagers.DNSRecord),
+		withFailures(withBlocked(buildCertificateCondition(capp, cappStatus, isRequired[rmanagers.Certificate]), blocked, rmanagers.Certificate), failures, rmanagers.Certificate),
+		withFailures(withBlocked(buildLoggingCondition(cappStatus, isRequired[rmanagers.SyslogNGFlow]), blocked, rmanagers.SyslogNGOutput, rmanagers.SyslogNGFlow), failures, rmanagers.SyslogNGOutput, rmanagers.SyslogNGFlow),
+		withFailures(withBlocked(buildVolumesCondition(cappStatus, isRequired[rmanagers.NfsPVC]), blocked, rmanagers.NfsPVC), failures, rmanagers.NfsPVC),
+		withFailures(withBlocked(buildSourcesCondition(cappStatus, isRequired[rmanagers.KedaSource]), blocked, rmanagers.KedaSource), failures, rmanagers.KedaSource),
+	}
+	subsystemConditions = append(subsystemConditions, buildReadyCondition(subsystemConditions))
+
+	for _, condition := range subsystemConditions {
+		condition.ObservedGeneration = capp.Generation
+		meta.SetStatusCondition(&conditions, condition)
+	}
+
+	return conditions
+}
+
+// withFailures replaces the given condition with a False condition if any of the given resource managers
+// failed to reconcile its resources.
+func withFailures(condition metav1.Condition, failures map[string]error, managers ...string) metav1.Condition {
+	var messages []string
+	for _, manager := range managers {
+		if err, ok := failures[manager]; ok {
+			messages = append(messages, fmt.Sprintf("%s: %s", manager, err.Error()))
+		}
+	}
+
+	if len(messages) == 0 {
+		return condition
+	}
+
+	return metav1.Condition{Type: condition.Type, Status: metav1.ConditionFalse, Reason: ReasonReconcileFailed, Message: strings.Join(messages, "; ")}
+}
+
+// withBlocked replaces the given condition with an Unknown condition if any of the given resource managers
+// is blocked, since its resources are not reconciled until the resources it depends on are ready.
+func withBlocked(condition metav1.Condition, blockedResources []cappv1alpha1.BlockedResource, managers ...string) metav1.Condition {
+	var messages []string
+	for _, blockedResource := range blockedResources {
+		if slices.Contains(managers, blockedResource.Name) {
+			messages = append(messages, fmt.Sprintf("%s: waiting for %s to be ready", blockedResource.Name, strings.Join(blockedResource.WaitingFor, ", ")))
+		}
+	}
+
+	if len(messages) == 0 {
+		return condition
+	}
+
+	return metav1.Condition{Type: condition.Type, Status: metav1.ConditionUnknown, Reason: ReasonBlocked, Message: strings.Join(messages, "; ")}
+}
+
+// buildReadyCondition combines the given subsystem conditions into the Ready condition.
+func buildReadyCondition(subsystemConditions []metav1.Condition) metav1.Condition {
+	var notReady, inProgress []string
+	for _, condition := range subsystemConditions {
+		if condition.Reason == ReasonDisabled {
+			return metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: ReasonDisabled, Message: condition.Message}
+		}
+
+		switch condition.Status {
+		case metav1.ConditionFalse:
+			notReady = append(notReady, condition.Type)
+		case metav1.ConditionUnknown:
+			inProgress = append(inProgress, condition.Type)
+		}
+	}
+
+	if len(notReady) > 0 {
+		return metav1.Condition{
+			Type:    ConditionTypeReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonNotReady,
+			Message: fmt.Sprintf("Conditions are not ready: %s", strings.Join(notReady, ", ")),
+		}
+	}
+
+	if len(inProgress) > 0 {
+		return metav1.Condition{
+			Type:    ConditionTypeReady,
+			Status:  metav1.ConditionUnknown,
+			Reason:  ReasonInProgress,
+			Message: fmt.Sprintf("Conditions are in progress: %s", strings.Join(inProgress, ", ")),
+		}
+	}
+
+	return metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: ReasonReady, Message: "Capp is ready"}
+}
+
+// buildSubsystemCondition combines the readiness of the objects of a subsystem into a condition of the given type.
+// A required subsystem without objects is in progress, since its objects have not been reported yet.
+func buildSubsystemCondition(conditionType string, isRequired bool, objects []objectReadiness) metav1.Condition {
+	if !isRequired {
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue, Reason: ReasonNotRequired, Message: "Not required by the Capp spec"}
+	}
+
+	if len(objects) == 0 {
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionUnknown, Reason: ReasonInProgress, Message: "Waiting for resources to be created"}
+	}
+
+	var notReady, inProgress []string
+	for _, object := range objects {
+		message := object.name
+		if object.message != "" {
+			message = fmt.Sprintf("%s: %s", object.name, object.message)
+		}
+
+		switch object.status {
+		case metav1.ConditionFalse:
+			notReady = append(notReady, message)
+		case metav1.ConditionUnknown:
+			inProgress = append(inProgress, message)
+		}
+	}
+
+	if len(notReady) > 0 {
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionFalse, Reason: ReasonNotReady, Message: strings.Join(notReady, "; ")}
+	}
+
+	if len(inProgress) > 0 {
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionUnknown, Reason: ReasonInProgress, Message: strings.Join(inProgress, "; ")}
+	}
+
+	return metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue, Reason: ReasonReady, Message: "All resources are ready"}
+}
+
+// buildKnativeServiceCondition builds the KnativeServiceReady condition from the Ready condition of the Knative Service.
+func buildKnativeServiceCondition(capp cappv1alpha1.Capp, cappStatus cappv1alpha1.CappStatus, isRequired bool) metav1.Condition {
+	if !isRequired {
+		return metav1.Condition{
+			Type:    ConditionTypeKnativeServiceReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonDisabled,
+			Message: fmt.Sprintf("Capp %q is disabled", capp.Name),
+		}
+	}
+
+	knativeObjectStatus := cappStatus.KnativeObjectStatus
+	return buildSubsystemCondition(ConditionTypeKnativeServiceReady, isRequired, []objectReadiness{
+		fromKnativeCondition(capp.Name, knativeObjectStatus.GetCondition(apis.ConditionReady)),
+	})
+}
+
+// buildRouteCondition builds the RouteReady condition from the Ready conditions of the DomainMappings of the Capp hostnames.
+func buildRouteCondition(cappStatus cappv1alpha1.CappStatus, isRequired bool) metav1.Condition {
+	//nolint:prealloc
+	var objects []objectReadiness
+	for _, hostnameStatus := range cappStatus.RouteStatus.HostnamesStatus {
+		domainMappingStatus := hostnameStatus.DomainMappingObjectStatus
+		objects = append(objects, fromKnativeCondition(hostnameStatus.Hostname, domainMappingStatus.GetCondition(apis.ConditionReady)))
+	}
+
+	return buildSubsystemCondition(ConditionTypeRouteReady, isRequired, objects)
+}
+
+// buildDNSCondition builds the DNSReady condition from the Ready conditions of the CNAMERecords of the Capp hostnames.
+func buildDNSCondition(cappStatus cappv1alpha1.CappStatus, isRequired bool) metav1.Condition {
+	//nolint:prealloc
+	var objects []objectReadiness
+	for _, hostnameStatus := range cappStatus.RouteStatus.HostnamesStatus {
+		cnameRecordStatus := hostnameStatus.DNSRecordObjectStatus.CNAMERecordObjectStatus
+		readyCondition := cnameRecordStatus.GetCondition(xpv1.TypeReady)
+		objects = append(objects, objectReadiness{
+			name:    hostnameStatus.Hostname,
+			status:  toConditionStatus(readyCondition.Status),
+			message: readyCondition.Message,
+		})
+	}
+
+	return buildSubsystemCondition(ConditionTypeDNSReady, isRequired, objects)
+}
+
+// buildCertificateCondition builds the CertificateReady condition from the Ready conditions of the Certificates
+// of the Capp hostnames which have TLS enabled.
+func buildCertificateCondition(capp cappv1alpha1.Capp, cappStatus cappv1alpha1.CappStatus, isRequired bool) metav1.Condition {
+	tlsEnabled := map[string]bool{}
+	for _, hostname := range capp.Spec.RouteSpec.AdditionalHostnames {
+		tlsEnabled[hostname.Hostname] = hostname.TlsEnabled
+	}
+	tlsEnabled[capp.Spec.RouteSpec.Hostname] = capp.Spec.RouteSpec.TlsEnabled
+
+	var objects []objectReadiness
+	for _, hostnameStatus := range cappStatus.RouteStatus.HostnamesStatus {
+		if !tlsEnabled[hostnameStatus.Hostname] {
+			continue
+		}
+
+		readiness := objectReadiness{name: hostnameStatus.Hostname, status: metav1.ConditionUnknown}
+		for _, condition := range hostnameStatus.CertificateObjectStatus.Conditions {
+			if condition.Type == cmapi.CertificateConditionReady {
+				readiness.status = fromCertManagerConditionStatus(condition.Status)
+				readiness.message = condition.Message
+			}
+		}
+		objects = append(objects, readiness)
+	}
+
+	return buildSubsystemCondition(ConditionTypeCertificateReady, isRequired, objects)
+}
+
+// buildLoggingCondition builds the LoggingReady condition from the logging condition of the Capp.
+func buildLoggingCondition(cappStatus cappv1alpha1.CappStatus, isRequired bool) metav1.Condition {
+	readiness := objectReadiness{name: "logging", status: metav1.ConditionUnknown}
+	if condition := meta.FindStatusCondition(cappStatus.LoggingStatus.Conditions, loggingReady); condition != nil {
+		readiness.status = condition.Status
+		if condition.Status != metav1.ConditionTrue {
+			readiness.message = condition.Reason
+		}
+	}
+
+	return buildSubsystemCondition(ConditionTypeLoggingReady, isRequired, []objectReadiness{readiness})
+}
+
+// buildVolumesCondition builds the VolumesReady condition from the phase of the PVCs of the NfsPvcs of the Capp.
+func buildVolumesCondition(cappStatus cappv1alpha1.CappStatus, isRequired bool) metav1.Condition {
+	//nolint:prealloc
+	var objects []objectReadiness
+	for _, volumeStatus := range cappStatus.VolumesStatus.NFSVolumesStatus {
+		readiness := objectReadiness{name: volumeStatus.VolumeName, status: metav1.ConditionTrue}
+		if phase := volumeStatus.NFSPVCStatus.PvcPhase; phase != nfsPVCBoundPhase {
+			readiness.status = metav1.ConditionUnknown
+			readiness.message = fmt.Sprintf("PVC phase is %q", phase)
+		}
+		objects = append(objects, readiness)
+	}
+
+	return buildSubsystemCondition(ConditionTypeVolumesReady, isRequired, objects)
+}
+
+// buildSourcesCondition builds the SourcesReady condition from the Ready conditions of the ScaledObjects of the Capp.
+func buildSourcesCondition(cappStatus cappv1alpha1.CappStatus, isRequired bool) metav1.Condition {
+	//nolint:prealloc
+	var objects []objectReadiness
+	for _, sourceStatus := range cappStatus.SourceStatus {
+		readiness := objectReadiness{name: sourceStatus.Name, status: metav1.ConditionUnknown}
+		if condition := meta.FindStatusCondition(sourceStatus.Conditions, string(kedav1alpha1.ConditionReady)); condition != nil {
+			readiness.status = condition.Status
+			readiness.message = condition.Message
+		}
+		objects = append(objects, readiness)
+	}
+
+	return buildSubsystemCondition(ConditionTypeSourcesReady, isRequired, objects)
+}
+
+// fromKnativeCondition returns the readiness of an object from its Knative Ready condition.
+func fromKnativeCondition(name string, condition *apis.Condition) objectReadiness {
+	if condition == nil {
+		return objectReadiness{name: name, status: metav1.ConditionUnknown}
+	}
+
+	return objectReadiness{name: name, status: toConditionStatus(condition.Status), message: condition.Message}
+}
+
+// toConditionStatus converts a core condition status to a metav1 condition status.
+func toConditionStatus(status corev1.ConditionStatus) metav1.ConditionStatus {
+	switch status {
+	case corev1.ConditionTrue:
+		return metav1.ConditionTrue
+	case corev1.ConditionFalse:
+		return metav1.ConditionFalse
+	default:
+		return metav1.ConditionUnknown
+	}
+}
+
+// fromCertManagerConditionStatus converts a cert-manager condition status to a metav1 condition status.
+func fromCertManagerConditionStatus(status cmmeta.ConditionStatus) metav1.ConditionStatus {
+	switch status {
+	case cmmeta.ConditionTrue:
+		return metav1.ConditionTrue
+	case cmmeta.ConditionFalse:
+		return metav1.ConditionFalse
+	default:
+		return metav1.ConditionUnknown
+	}
+}
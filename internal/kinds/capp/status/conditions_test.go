@@ -0,0 +1,149 @@
+package status
+
+import (
+	"errors"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func knativeStatus(status corev1.ConditionStatus) knativev1.ServiceStatus {
+	serviceStatus := knativev1.ServiceStatus{}
+	serviceStatus.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: status, Message: "revision failed"}}
+	return serviceStatus
+}
+
+func TestBuildConditions(t *testing.T) {
+	capp := cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Generation: 3}}
+
+	tests := []struct {
+		name              string
+		cappStatus        cappv1alpha1.CappStatus
+		isRequired        map[string]bool
+		failures          map[string]error
+		expectedReady     metav1.ConditionStatus
+		expectedReason    string
+		expectedKnative   metav1.ConditionStatus
+		expectedVolumes   metav1.ConditionStatus
+		expectedMessageOf string
+	}{
+		{
+			name:            "Ready when every required subsystem is ready",
+			cappStatus:      cappv1alpha1.CappStatus{KnativeObjectStatus: knativeStatus(corev1.ConditionTrue)},
+			isRequired:      map[string]bool{rmanagers.KnativeServing: true},
+			expectedReady:   metav1.ConditionTrue,
+			expectedReason:  ReasonReady,
+			expectedKnative: metav1.ConditionTrue,
+			expectedVolumes: metav1.ConditionTrue,
+		},
+		{
+			name:              "Not ready when the Knative Service failed",
+			cappStatus:        cappv1alpha1.CappStatus{KnativeObjectStatus: knativeStatus(corev1.ConditionFalse)},
+			isRequired:        map[string]bool{rmanagers.KnativeServing: true},
+			expectedReady:     metav1.ConditionFalse,
+			expectedReason:    ReasonNotReady,
+			expectedKnative:   metav1.ConditionFalse,
+			expectedVolumes:   metav1.ConditionTrue,
+			expectedMessageOf: ConditionTypeKnativeServiceReady,
+		},
+		{
+			name: "In progress when a volume is not bound yet",
+			cappStatus: cappv1alpha1.CappStatus{
+				KnativeObjectStatus: knativeStatus(corev1.ConditionTrue),
+				VolumesStatus: cappv1alpha1.VolumesStatus{NFSVolumesStatus: []cappv1alpha1.NFSVolumeStatus{
+					{VolumeName: "data"},
+				}},
+			},
+			isRequired:        map[string]bool{rmanagers.KnativeServing: true, rmanagers.NfsPVC: true},
+			expectedReady:     metav1.ConditionUnknown,
+			expectedReason:    ReasonInProgress,
+			expectedKnative:   metav1.ConditionTrue,
+			expectedVolumes:   metav1.ConditionUnknown,
+			expectedMessageOf: ConditionTypeVolumesReady,
+		},
+		{
+			name:              "In progress when a required subsystem has no resources yet",
+			cappStatus:        cappv1alpha1.CappStatus{KnativeObjectStatus: knativeStatus(corev1.ConditionTrue)},
+			isRequired:        map[string]bool{rmanagers.KnativeServing: true, rmanagers.NfsPVC: true},
+			expectedReady:     metav1.ConditionUnknown,
+			expectedReason:    ReasonInProgress,
+			expectedKnative:   metav1.ConditionTrue,
+			expectedVolumes:   metav1.ConditionUnknown,
+			expectedMessageOf: ConditionTypeVolumesReady,
+		},
+		{
+			name:              "Not ready when a resource manager failed",
+			cappStatus:        cappv1alpha1.CappStatus{KnativeObjectStatus: knativeStatus(corev1.ConditionTrue)},
+			isRequired:        map[string]bool{rmanagers.KnativeServing: true},
+			failures:          map[string]error{rmanagers.NfsPVC: errors.New("nfs server unreachable")},
+			expectedReady:     metav1.ConditionFalse,
+			expectedReason:    ReasonNotReady,
+			expectedKnative:   metav1.ConditionTrue,
+			expectedVolumes:   metav1.ConditionFalse,
+			expectedMessageOf: ConditionTypeVolumesReady,
+		},
+		{
+			name:            "Not ready when the Capp is disabled",
+			isRequired:      map[string]bool{},
+			expectedReady:   metav1.ConditionFalse,
+			expectedReason:  ReasonDisabled,
+			expectedKnative: metav1.ConditionFalse,
+			expectedVolumes: metav1.ConditionTrue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := buildConditions(capp, tt.cappStatus, tt.isRequired, tt.failures)
+			assert.Len(t, conditions, 8)
+
+			ready := meta.FindStatusCondition(conditions, ConditionTypeReady)
+			assert.Equal(t, tt.expectedReady, ready.Status)
+			assert.Equal(t, tt.expectedReason, ready.Reason)
+			assert.Equal(t, capp.Generation, ready.ObservedGeneration)
+			assert.Contains(t, ready.Message, tt.expectedMessageOf)
+
+			assert.Equal(t, tt.expectedKnative, meta.FindStatusCondition(conditions, ConditionTypeKnativeServiceReady).Status)
+			assert.Equal(t, tt.expectedVolumes, meta.FindStatusCondition(conditions, ConditionTypeVolumesReady).Status)
+		})
+	}
+}
+
+func TestBuildConditionsKeepsTransitionTime(t *testing.T) {
+	capp := cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "test-capp"}}
+	isRequired := map[string]bool{rmanagers.KnativeServing: true}
+	cappStatus := cappv1alpha1.CappStatus{KnativeObjectStatus: knativeStatus(corev1.ConditionTrue)}
+
+	cappStatus.Conditions = buildConditions(capp, cappStatus, isRequired, nil)
+	meta.FindStatusCondition(cappStatus.Conditions, ConditionTypeReady).LastTransitionTime = metav1.Unix(0, 0)
+
+	conditions := buildConditions(capp, cappStatus, isRequired, nil)
+	assert.Equal(t, metav1.Unix(0, 0), meta.FindStatusCondition(conditions, ConditionTypeReady).LastTransitionTime)
+}
+
+func TestBuildConditionsBlocked(t *testing.T) {
+	capp := cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "test-capp"}}
+	isRequired := map[string]bool{rmanagers.KnativeServing: true, rmanagers.DomainMapping: true}
+	cappStatus := cappv1alpha1.CappStatus{
+		KnativeObjectStatus: knativeStatus(corev1.ConditionTrue),
+		BlockedResources: []cappv1alpha1.BlockedResource{
+			{Name: rmanagers.DomainMapping, WaitingFor: []string{rmanagers.Certificate}},
+		},
+	}
+
+	conditions := buildConditions(capp, cappStatus, isRequired, nil)
+
+	route := meta.FindStatusCondition(conditions, ConditionTypeRouteReady)
+	assert.Equal(t, metav1.ConditionUnknown, route.Status)
+	assert.Equal(t, ReasonBlocked, route.Reason)
+	assert.Contains(t, route.Message, rmanagers.Certificate)
+	assert.Equal(t, metav1.ConditionUnknown, meta.FindStatusCondition(conditions, ConditionTypeReady).Status)
+}
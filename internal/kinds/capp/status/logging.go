@@ -5,6 +5,7 @@ import (
 	"time"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 	"github.com/go-logr/logr"
 	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -20,36 +21,52 @@ const (
 )
 
 // buildLoggingStatus builds the Logging status of the Capp CRD by getting the SyslogNGFlow and SyslogNGOutput objects
-// bundled to the Capp and adding their status. It also creates a condition in accordance with their situation.
+// of every log destination of the Capp and adding their status. It also creates a condition in accordance with their situation.
 func buildLoggingStatus(ctx context.Context, capp cappv1alpha1.Capp, log logr.Logger, r client.Client, isRequired bool) (cappv1alpha1.LoggingStatus, error) {
-	logger := log.WithValues("SyslogNGFlowName", capp.Name, "SyslogNGOutputName", capp.Name)
+	logger := log.WithValues("CappName", capp.Name)
 	loggingStatus := cappv1alpha1.LoggingStatus{}
 
 	if !isRequired {
 		return loggingStatus, nil
 	}
 
-	syslogNGFlow := &loggingv1beta1.SyslogNGFlow{}
 	logger.Info("Building logger status")
 
-	if err := r.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name}, syslogNGFlow); err != nil {
-		logger.Error(err, "Failed to fetch SyslogNGFlow")
-		return loggingStatus, err
-	}
+	var problemsCount int
+	for _, destination := range utils.GetLogDestinations(capp) {
+		name := utils.GetLogDestinationResourceName(capp.Name, destination.Name)
 
-	syslogNGOutput := &loggingv1beta1.SyslogNGOutput{}
-	if err := r.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: capp.Name}, syslogNGOutput); err != nil {
-		logger.Error(err, "Failed to fetch SyslogNGOutput")
-		return loggingStatus, err
-	}
+		syslogNGFlow := &loggingv1beta1.SyslogNGFlow{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: name}, syslogNGFlow); err != nil {
+			logger.Error(err, "Failed to fetch SyslogNGFlow", "SyslogNGFlowName", name)
+			return loggingStatus, err
+		}
+
+		syslogNGOutput := &loggingv1beta1.SyslogNGOutput{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: name}, syslogNGOutput); err != nil {
+			logger.Error(err, "Failed to fetch SyslogNGOutput", "SyslogNGOutputName", name)
+			return loggingStatus, err
+		}
 
-	loggingStatus.SyslogNGFlow = syslogNGFlow.Status
-	loggingStatus.SyslogNGOutput = syslogNGOutput.Status
+		problemsCount += syslogNGFlow.Status.ProblemsCount + syslogNGOutput.Status.ProblemsCount
+		if destination.Name == "" {
+			loggingStatus.SyslogNGFlow = syslogNGFlow.Status
+			loggingStatus.SyslogNGOutput = syslogNGOutput.Status
+			continue
+		}
+
+		loggingStatus.Destinations = append(loggingStatus.Destinations, cappv1alpha1.LogDestinationStatus{
+			Name:           destination.Name,
+			Output:         name,
+			SyslogNGFlow:   syslogNGFlow.Status,
+			SyslogNGOutput: syslogNGOutput.Status,
+		})
+	}
 
 	problems := "True"
 	reason := conditionReady
 
-	if syslogNGFlow.Status.ProblemsCount != 0 || syslogNGOutput.Status.ProblemsCount != 0 {
+	if problemsCount != 0 {
 		reason = loggingResourceInvalid
 		problems = "False"
 	}
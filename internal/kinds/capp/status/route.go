@@ -6,10 +6,12 @@ import (
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 
 	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,7 +22,7 @@ import (
 func buildRouteStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired map[string]bool) (cappv1alpha1.RouteStatus, error) {
 	routeStatus := cappv1alpha1.RouteStatus{}
 
-	dnsConfig, err := utils.GetDNSConfig(ctx, kubeClient)
+	dnsConfig, err := utils.GetDNSConfig(ctx, kubeClient, capp.Namespace)
 	if err != nil {
 		return routeStatus, err
 	}
@@ -30,17 +32,29 @@ func buildRouteStatus(ctx context.Context, kubeClient client.Client, capp cappv1
 		return routeStatus, err
 	}
 
-	domainMappingStatus, err := buildDomainMappingStatus(ctx, kubeClient, capp, isRequired[rmanagers.DomainMapping], zone)
+	hostname := capp.Spec.RouteSpec.Hostname
+	domainMappingStatus, err := buildDomainMappingStatus(ctx, kubeClient, capp, hostname, isRequired[rmanagers.DomainMapping], zone)
 	if err != nil {
 		return routeStatus, err
 	}
 
-	dnsRecordStatus, err := buildDNSRecordStatus(ctx, kubeClient, capp, isRequired[rmanagers.DNSRecord], zone)
+	dnsRecordStatus, err := buildDNSRecordStatus(ctx, kubeClient, capp, hostname, isRequired[rmanagers.DNSRecord], zone)
 	if err != nil {
 		return routeStatus, err
 	}
 
-	certificateStatus, err := buildCertificateStatus(ctx, kubeClient, capp, isRequired[rmanagers.Certificate], zone)
+	certificateRequired := isRequired[rmanagers.Certificate] && capp.Spec.RouteSpec.TlsEnabled
+	certificateStatus, err := buildCertificateStatus(ctx, kubeClient, capp, hostname, certificateRequired, zone)
+	if err != nil {
+		return routeStatus, err
+	}
+
+	hostnamesStatus, err := buildHostnamesStatus(ctx, kubeClient, capp, isRequired, zone)
+	if err != nil {
+		return routeStatus, err
+	}
+
+	previewURLs, err := buildPreviewURLs(ctx, kubeClient, capp, isRequired[rmanagers.DomainMapping], zone)
 	if err != nil {
 		return routeStatus, err
 	}
@@ -48,37 +62,108 @@ func buildRouteStatus(ctx context.Context, kubeClient client.Client, capp cappv1
 	routeStatus.DomainMappingObjectStatus = domainMappingStatus
 	routeStatus.DNSRecordObjectStatus = dnsRecordStatus
 	routeStatus.CertificateObjectStatus = certificateStatus
+	routeStatus.PreviewURLs = previewURLs
+	routeStatus.HostnamesStatus = hostnamesStatus
 
 	return routeStatus, nil
 }
 
+// buildHostnamesStatus partly constructs the Route Status of the Capp object in accordance to the
+// status of the DomainMapping, DNSRecord and Certificate objects of every custom hostname.
+func buildHostnamesStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired map[string]bool, zone string) ([]cappv1alpha1.HostnameStatus, error) {
+	var hostnamesStatus []cappv1alpha1.HostnameStatus
+	for _, hostname := range utils.GetCustomHostnames(capp.Spec.RouteSpec) {
+		hostnameStatus := cappv1alpha1.HostnameStatus{Hostname: hostname.Hostname}
+		var err error
+
+		hostnameStatus.DomainMappingObjectStatus, err = buildDomainMappingStatus(ctx, kubeClient, capp, hostname.Hostname, isRequired[rmanagers.DomainMapping], zone)
+		if err != nil {
+			return nil, err
+		}
+
+		hostnameStatus.DNSRecordObjectStatus, err = buildDNSRecordStatus(ctx, kubeClient, capp, hostname.Hostname, isRequired[rmanagers.DNSRecord], zone)
+		if err != nil {
+			return nil, err
+		}
+
+		certificateRequired := isRequired[rmanagers.Certificate] && hostname.TlsEnabled
+		hostnameStatus.CertificateObjectStatus, err = buildCertificateStatus(ctx, kubeClient, capp, hostname.Hostname, certificateRequired, zone)
+		if err != nil {
+			return nil, err
+		}
+
+		hostnamesStatus = append(hostnamesStatus, hostnameStatus)
+	}
+
+	return hostnamesStatus, nil
+}
+
 // buildDomainMappingStatus partly constructs the Route Status of the Capp object in accordance to the
-// status of the corresponding DomainMapping object.
-func buildDomainMappingStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zone string) (knativev1beta1.DomainMappingStatus, error) {
+// status of the DomainMapping object of the given hostname. A DomainMapping which was not created yet has an empty status.
+func buildDomainMappingStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, hostname string, isRequired bool, zone string) (knativev1beta1.DomainMappingStatus, error) {
 	if !isRequired {
 		return knativev1beta1.DomainMappingStatus{}, nil
 	}
 
 	domainMapping := &knativev1beta1.DomainMapping{}
-	domainMappingName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
+	domainMappingName := utils.GenerateResourceName(hostname, zone)
 	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: domainMappingName}, domainMapping); err != nil {
+		if errors.IsNotFound(err) {
+			return knativev1beta1.DomainMappingStatus{}, nil
+		}
 		return knativev1beta1.DomainMappingStatus{}, err
 	}
 
 	return domainMapping.Status, nil
 }
 
+// buildPreviewURLs partly constructs the Route Status of the Capp object in accordance to the
+// status of the DomainMapping objects of the Capp previews.
+func buildPreviewURLs(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zone string) ([]cappv1alpha1.PreviewURL, error) {
+	if !isRequired {
+		return nil, nil
+	}
+
+	var previewURLs []cappv1alpha1.PreviewURL
+	for _, previewRoute := range utils.GetPreviewRoutes(capp.Spec.RouteSpec, rollout.GetTrafficTargets(capp)) {
+		previewURL := cappv1alpha1.PreviewURL{
+			Tag:          previewRoute.Tag,
+			RevisionName: previewRoute.RevisionName,
+		}
+
+		domainMapping := &knativev1beta1.DomainMapping{}
+		domainMappingName := utils.GenerateResourceName(previewRoute.Hostname, zone)
+		if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: domainMappingName}, domainMapping); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+		} else {
+			if domainMapping.Status.URL != nil {
+				previewURL.URL = domainMapping.Status.URL.String()
+			}
+			previewURL.Ready = domainMapping.Status.IsReady()
+		}
+
+		previewURLs = append(previewURLs, previewURL)
+	}
+
+	return previewURLs, nil
+}
+
 // buildCertificateStatus partly constructs the Route Status of the Capp object in accordance to the
-// status of the corresponding Certificate object.
-func buildCertificateStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zone string) (cmapi.CertificateStatus, error) {
+// status of the Certificate object of the given hostname. A Certificate which was not created yet has an empty status.
+func buildCertificateStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, hostname string, isRequired bool, zone string) (cmapi.CertificateStatus, error) {
 	if !isRequired {
 		return cmapi.CertificateStatus{}, nil
 	}
 
 	certificate := &cmapi.Certificate{}
-	certificateName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
+	certificateName := utils.GenerateResourceName(hostname, zone)
 
 	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: certificateName}, certificate); err != nil {
+		if errors.IsNotFound(err) {
+			return cmapi.CertificateStatus{}, nil
+		}
 		return cmapi.CertificateStatus{}, err
 	}
 
@@ -86,8 +171,8 @@ func buildCertificateStatus(ctx context.Context, kubeClient client.Client, capp
 }
 
 // buildDNSRecordStatus partly constructs the Route Status of the Capp object in accordance to the
-// status of the corresponding DNSRecord object.
-func buildDNSRecordStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool, zone string) (cappv1alpha1.DNSRecordObjectStatus, error) {
+// status of the DNSRecord object of the given hostname.
+func buildDNSRecordStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, hostname string, isRequired bool, zone string) (cappv1alpha1.DNSRecordObjectStatus, error) {
 	dnsStatus := cappv1alpha1.DNSRecordObjectStatus{}
 	var err error
 
@@ -95,7 +180,7 @@ func buildDNSRecordStatus(ctx context.Context, kubeClient client.Client, capp ca
 		return dnsStatus, nil
 	}
 
-	dnsStatus.CNAMERecordObjectStatus, err = buildCNAMERecordStatus(ctx, kubeClient, capp, zone)
+	dnsStatus.CNAMERecordObjectStatus, err = buildCNAMERecordStatus(ctx, kubeClient, capp, hostname, zone)
 	if err != nil {
 		return dnsStatus, err
 	}
@@ -104,11 +189,14 @@ func buildDNSRecordStatus(ctx context.Context, kubeClient client.Client, capp ca
 }
 
 // buildCNAMERecordStatus partly constructs the Route Status of the Capp object in accordance to the
-// status of the corresponding CNAMERecord object.
-func buildCNAMERecordStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, zone string) (dnsrecordv1alpha1.CNAMERecordStatus, error) {
+// status of the CNAMERecord object of the given hostname. A CNAMERecord which was not created yet has an empty status.
+func buildCNAMERecordStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, hostname, zone string) (dnsrecordv1alpha1.CNAMERecordStatus, error) {
 	cnameRecord := &dnsrecordv1alpha1.CNAMERecord{}
-	cnameRecordName := utils.GenerateResourceName(capp.Spec.RouteSpec.Hostname, zone)
+	cnameRecordName := utils.GenerateResourceName(hostname, zone)
 	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: cnameRecordName}, cnameRecord); err != nil {
+		if errors.IsNotFound(err) {
+			return dnsrecordv1alpha1.CNAMERecordStatus{}, nil
+		}
 		return dnsrecordv1alpha1.CNAMERecordStatus{}, err
 	}
 
@@ -0,0 +1,37 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	rmanagers "github.com/dana-team/container-app-operator/internal/kinds/capp/resourcemanagers"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuildHostnamesStatusNotCreatedYet(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, knativev1beta1.AddToScheme(scheme))
+	assert.NoError(t, cmapi.AddToScheme(scheme))
+	assert.NoError(t, dnsrecordv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"},
+		Spec: cappv1alpha1.CappSpec{RouteSpec: cappv1alpha1.RouteSpec{
+			Hostname:   "app.example.com",
+			TlsEnabled: true,
+		}},
+	}
+	isRequired := map[string]bool{rmanagers.DomainMapping: true, rmanagers.DNSRecord: true, rmanagers.Certificate: true}
+
+	hostnamesStatus, err := buildHostnamesStatus(context.Background(), k8sClient, capp, isRequired, "example.com.")
+	assert.NoError(t, err)
+	assert.Equal(t, []cappv1alpha1.HostnameStatus{{Hostname: "app.example.com"}}, hostnamesStatus)
+}
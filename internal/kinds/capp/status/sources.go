@@ -0,0 +1,80 @@
+package status
+
+import (
+	"context"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const unknownReason = "Unknown"
+
+// buildSourcesStatus constructs the Sources Status of the Capp object in accordance to the status of the corresponding
+// ScaledObjects. The Ready and Active conditions of each ScaledObject are mirrored and keyed by the source name.
+func buildSourcesStatus(ctx context.Context, kubeClient client.Client, capp cappv1alpha1.Capp, isRequired bool) ([]cappv1alpha1.KedaStatus, error) {
+	//nolint:prealloc
+	var sourcesStatus []cappv1alpha1.KedaStatus
+
+	if !isRequired {
+		return sourcesStatus, nil
+	}
+
+	for _, source := range capp.Spec.Sources {
+		sourceStatus := cappv1alpha1.KedaStatus{
+			Name:       source.Name,
+			Conditions: getPreviousSourceConditions(capp, source.Name),
+		}
+
+		scaledObject := kedav1alpha1.ScaledObject{}
+		if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: capp.Namespace, Name: source.Name}, &scaledObject); err != nil {
+			if !errors.IsNotFound(err) {
+				return sourcesStatus, err
+			}
+		} else {
+			setSourceCondition(&sourceStatus.Conditions, scaledObject.Status.Conditions.GetReadyCondition(), scaledObject.Generation)
+			setSourceCondition(&sourceStatus.Conditions, scaledObject.Status.Conditions.GetActiveCondition(), scaledObject.Generation)
+		}
+
+		sourcesStatus = append(sourcesStatus, sourceStatus)
+	}
+
+	return sourcesStatus, nil
+}
+
+// getPreviousSourceConditions returns the conditions which are currently reported for the source with the given name.
+// Re-using them keeps the LastTransitionTime of conditions whose status has not changed.
+func getPreviousSourceConditions(capp cappv1alpha1.Capp, name string) []metav1.Condition {
+	for _, sourceStatus := range capp.Status.SourceStatus {
+		if sourceStatus.Name == name {
+			return sourceStatus.Conditions
+		}
+	}
+
+	return nil
+}
+
+// setSourceCondition converts a KEDA condition to a metav1.Condition and sets it on the given conditions.
+// Conditions which KEDA has not reported yet are skipped.
+func setSourceCondition(conditions *[]metav1.Condition, kedaCondition kedav1alpha1.Condition, generation int64) {
+	if kedaCondition.Type == "" {
+		return
+	}
+
+	reason := kedaCondition.Reason
+	if reason == "" {
+		reason = unknownReason
+	}
+
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               string(kedaCondition.Type),
+		Status:             kedaCondition.Status,
+		Reason:             reason,
+		Message:            kedaCondition.Message,
+		ObservedGeneration: generation,
+	})
+}
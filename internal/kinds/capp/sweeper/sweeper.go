@@ -0,0 +1,190 @@
+package sweeper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/dana-team/container-app-operator/internal/metrics"
+	nfspvcv1alpha1 "github.com/dana-team/nfspvc-operator/api/v1alpha1"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	eventOrphanDetected = "OrphanDetected"
+	eventOrphanDeleted  = "OrphanDeleted"
+)
+
+// managedKinds maps the kinds swept for orphans to a function returning an empty list of the kind.
+var managedKinds = map[string]func() client.ObjectList{
+	"CNAMERecord":   func() client.ObjectList { return &dnsrecordv1alpha1.CNAMERecordList{} },
+	"Certificate":   func() client.ObjectList { return &cmapi.CertificateList{} },
+	"DomainMapping": func() client.ObjectList { return &knativev1beta1.DomainMappingList{} },
+	"NfsPvc":        func() client.ObjectList { return &nfspvcv1alpha1.NfsPvcList{} },
+}
+
+// orphanedAtAnnotationKey is the annotation recording when a resource was first found orphaned. It is stored
+// on the resource, so that the grace period is not reset when the operator restarts or the leader changes.
+var orphanedAtAnnotationKey = utils.CappAPIGroup + "/orphaned-at"
+
+// OrphanSweeper periodically deletes resources managed by Capp whose parent Capp no longer exists. This happens
+// when the operator is down while a Capp is deleted, or when the finalizer of a Capp is removed by hand.
+// An orphan is deleted only once the grace period has passed since it was first found. In dry-run mode
+// orphans are only reported, using events and metrics.
+type OrphanSweeper struct {
+	Client        client.Client
+	Log           logr.Logger
+	EventRecorder record.EventRecorder
+	Interval      time.Duration
+	GracePeriod   time.Duration
+	DryRun        bool
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that only the leader sweeps orphans.
+func (s *OrphanSweeper) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable. It sweeps orphans every interval until the context is done.
+func (s *OrphanSweeper) Start(ctx context.Context) error {
+	s.Log.Info("starting orphan sweeper", "interval", s.Interval, "gracePeriod", s.GracePeriod, "dryRun", s.DryRun)
+	wait.UntilWithContext(ctx, s.Sweep, s.Interval)
+	return nil
+}
+
+// Sweep lists the resources of every managed kind and handles those whose parent Capp does not exist.
+// Resources which were found orphaned before but whose parent Capp exists again are no longer marked as orphans.
+func (s *OrphanSweeper) Sweep(ctx context.Context) {
+	for kind, newList := range managedKinds {
+		orphans, reclaimed, err := s.findOrphans(ctx, newList())
+		if err != nil {
+			s.Log.Error(err, fmt.Sprintf("failed to find orphaned %s resources", kind))
+			continue
+		}
+
+		metrics.OrphanedResources.WithLabelValues(kind).Set(float64(len(orphans)))
+		for _, orphan := range orphans {
+			s.handleOrphan(ctx, kind, orphan)
+		}
+
+		for _, object := range reclaimed {
+			if err := s.setOrphanedAt(ctx, object, ""); err != nil {
+				s.Log.Error(err, fmt.Sprintf("failed to unmark %s %q as orphaned", kind, object.GetName()), "namespace", object.GetNamespace())
+			}
+		}
+	}
+}
+
+// findOrphans returns the resources of the given list type which are managed by Capp and whose parent Capp
+// does not exist, and the resources which are marked as orphans but whose parent Capp exists.
+func (s *OrphanSweeper) findOrphans(ctx context.Context, list client.ObjectList) ([]client.Object, []client.Object, error) {
+	selector := labels.SelectorFromSet(labels.Set{utils.ManagedByLabelKey: utils.CappKey})
+	if err := s.Client.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, nil, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var orphans, reclaimed []client.Object
+	for _, item := range items {
+		object, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+
+		cappName := object.GetLabels()[utils.CappResourceKey]
+		if cappName == "" {
+			continue
+		}
+
+		capp := cappv1alpha1.Capp{}
+		if err := s.Client.Get(ctx, types.NamespacedName{Namespace: parentNamespace(object), Name: cappName}, &capp); err != nil {
+			if errors.IsNotFound(err) {
+				orphans = append(orphans, object)
+				continue
+			}
+			return nil, nil, err
+		}
+
+		if _, ok := object.GetAnnotations()[orphanedAtAnnotationKey]; ok {
+			reclaimed = append(reclaimed, object)
+		}
+	}
+
+	return orphans, reclaimed, nil
+}
+
+// parentNamespace returns the namespace of the parent Capp of a resource. Resources which may live outside
+// the namespace of their Capp, such as CNAMERecords, carry it in a label; otherwise it is their own namespace.
+func parentNamespace(object client.Object) string {
+	if namespace := object.GetLabels()[utils.CappNamespaceKey]; namespace != "" {
+		return namespace
+	}
+	return object.GetNamespace()
+}
+
+// handleOrphan marks and reports an orphan the first time it is found, and deletes it once the grace period
+// has passed since then, unless running in dry-run mode.
+func (s *OrphanSweeper) handleOrphan(ctx context.Context, kind string, orphan client.Object) {
+	orphanedAt, err := time.Parse(time.RFC3339, orphan.GetAnnotations()[orphanedAtAnnotationKey])
+	if err != nil {
+		orphanedAt = time.Now()
+		if err := s.setOrphanedAt(ctx, orphan, orphanedAt.UTC().Format(time.RFC3339)); err != nil {
+			s.Log.Error(err, fmt.Sprintf("failed to mark %s %q as orphaned", kind, orphan.GetName()), "namespace", orphan.GetNamespace())
+			return
+		}
+
+		s.Log.Info(fmt.Sprintf("found orphaned %s %q", kind, orphan.GetName()), "namespace", orphan.GetNamespace(), "dryRun", s.DryRun)
+		s.EventRecorder.Event(orphan, corev1.EventTypeWarning, eventOrphanDetected,
+			fmt.Sprintf("Parent Capp %s of %s %s no longer exists", orphan.GetLabels()[utils.CappResourceKey], kind, orphan.GetName()))
+	}
+
+	if s.DryRun || time.Since(orphanedAt) < s.GracePeriod {
+		return
+	}
+
+	if err := s.Client.Delete(ctx, orphan); err != nil && !errors.IsNotFound(err) {
+		s.Log.Error(err, fmt.Sprintf("failed to delete orphaned %s %q", kind, orphan.GetName()), "namespace", orphan.GetNamespace())
+		return
+	}
+
+	s.Log.Info(fmt.Sprintf("deleted orphaned %s %q", kind, orphan.GetName()), "namespace", orphan.GetNamespace())
+	s.EventRecorder.Event(orphan, corev1.EventTypeNormal, eventOrphanDeleted,
+		fmt.Sprintf("Deleted %s %s since its parent Capp no longer exists", kind, orphan.GetName()))
+	metrics.OrphanedResourcesDeleted.WithLabelValues(kind).Inc()
+}
+
+// setOrphanedAt patches the annotation recording when the given resource was first found orphaned.
+// An empty value removes the annotation.
+func (s *OrphanSweeper) setOrphanedAt(ctx context.Context, object client.Object, orphanedAt string) error {
+	patch := client.MergeFrom(object.DeepCopyObject().(client.Object))
+
+	annotations := object.GetAnnotations()
+	if orphanedAt == "" {
+		delete(annotations, orphanedAtAnnotationKey)
+	} else {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[orphanedAtAnnotationKey] = orphanedAt
+	}
+	object.SetAnnotations(annotations)
+
+	return s.Client.Patch(ctx, object, patch)
+}
@@ -0,0 +1,142 @@
+package sweeper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	nfspvcv1alpha1 "github.com/dana-team/nfspvc-operator/api/v1alpha1"
+	dnsrecordv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	knativev1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testNamespace = "test-ns"
+
+func newFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, cmapi.AddToScheme(scheme))
+	assert.NoError(t, dnsrecordv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, knativev1beta1.AddToScheme(scheme))
+	assert.NoError(t, nfspvcv1alpha1.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func newCertificate(name, cappName string) *cmapi.Certificate {
+	return &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				utils.CappResourceKey:   cappName,
+				utils.ManagedByLabelKey: utils.CappKey,
+			},
+		},
+	}
+}
+
+func certificateExists(t *testing.T, k8sClient client.Client, name string) bool {
+	err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: testNamespace, Name: name}, &cmapi.Certificate{})
+	if errors.IsNotFound(err) {
+		return false
+	}
+	assert.NoError(t, err)
+	return true
+}
+
+func getCertificate(t *testing.T, k8sClient client.Client, name string) *cmapi.Certificate {
+	certificate := &cmapi.Certificate{}
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: testNamespace, Name: name}, certificate))
+	return certificate
+}
+
+func TestSweep(t *testing.T) {
+	capp := &cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "existing-capp", Namespace: testNamespace}}
+	k8sClient := newFakeClient(t, capp, newCertificate("owned", "existing-capp"), newCertificate("orphan", "deleted-capp"))
+
+	newSweeper := func() *OrphanSweeper {
+		return &OrphanSweeper{
+			Client:        k8sClient,
+			Log:           logr.Discard(),
+			EventRecorder: record.NewFakeRecorder(10),
+			GracePeriod:   time.Hour,
+		}
+	}
+
+	newSweeper().Sweep(context.Background())
+	assert.True(t, certificateExists(t, k8sClient, "orphan"), "Expected the orphan to be kept during the grace period")
+	assert.Contains(t, getCertificate(t, k8sClient, "orphan").Annotations, orphanedAtAnnotationKey)
+	assert.NotContains(t, getCertificate(t, k8sClient, "owned").Annotations, orphanedAtAnnotationKey)
+
+	orphan := getCertificate(t, k8sClient, "orphan")
+	orphan.Annotations[orphanedAtAnnotationKey] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	assert.NoError(t, k8sClient.Update(context.Background(), orphan))
+
+	// a new sweeper, as after a restart of the operator, keeps counting the grace period from the annotation
+	newSweeper().Sweep(context.Background())
+	assert.False(t, certificateExists(t, k8sClient, "orphan"), "Expected the orphan to be deleted after the grace period")
+	assert.True(t, certificateExists(t, k8sClient, "owned"), "Expected a resource of an existing Capp to be kept")
+}
+
+func TestSweepReclaimed(t *testing.T) {
+	capp := &cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "recreated-capp", Namespace: testNamespace}}
+	certificate := newCertificate("reclaimed", "recreated-capp")
+	certificate.Annotations = map[string]string{orphanedAtAnnotationKey: time.Now().UTC().Format(time.RFC3339)}
+	k8sClient := newFakeClient(t, capp, certificate)
+
+	sweeper := &OrphanSweeper{Client: k8sClient, Log: logr.Discard(), EventRecorder: record.NewFakeRecorder(10)}
+	sweeper.Sweep(context.Background())
+	assert.NotContains(t, getCertificate(t, k8sClient, "reclaimed").Annotations, orphanedAtAnnotationKey)
+}
+
+func TestSweepCNAMERecordInOtherNamespace(t *testing.T) {
+	capp := &cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "existing-capp", Namespace: testNamespace}}
+	cnameRecord := &dnsrecordv1alpha1.CNAMERecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app.example.com",
+			Namespace: "dns-records",
+			Labels: map[string]string{
+				utils.CappResourceKey:   "existing-capp",
+				utils.CappNamespaceKey:  testNamespace,
+				utils.ManagedByLabelKey: utils.CappKey,
+			},
+		},
+	}
+	k8sClient := newFakeClient(t, capp, cnameRecord)
+	eventRecorder := record.NewFakeRecorder(10)
+
+	sweeper := &OrphanSweeper{Client: k8sClient, Log: logr.Discard(), EventRecorder: eventRecorder}
+	sweeper.Sweep(context.Background())
+	assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cnameRecord), cnameRecord))
+	assert.Empty(t, eventRecorder.Events, "Expected the CNAMERecord of an existing Capp in another namespace not to be an orphan")
+}
+
+func TestSweepDryRun(t *testing.T) {
+	k8sClient := newFakeClient(t, newCertificate("orphan", "deleted-capp"))
+	eventRecorder := record.NewFakeRecorder(10)
+
+	sweeper := &OrphanSweeper{
+		Client:        k8sClient,
+		Log:           logr.Discard(),
+		EventRecorder: eventRecorder,
+		DryRun:        true,
+	}
+
+	sweeper.Sweep(context.Background())
+	sweeper.Sweep(context.Background())
+	assert.True(t, certificateExists(t, k8sClient, "orphan"), "Expected the orphan to be kept in dry-run mode")
+	assert.Len(t, eventRecorder.Events, 1, "Expected the orphan to be reported once")
+	assert.Contains(t, <-eventRecorder.Events, eventOrphanDetected)
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ResolveCappConfig returns the CappConfig of the Capps in the given namespace. It is the cluster CappConfig,
+// with the fields set in the CappConfigs that select the namespace overriding its values.
+func ResolveCappConfig(ctx context.Context, k8sClient client.Client, namespace string) (*cappv1alpha1.CappConfig, error) {
+	appliedConfigs, err := GetAppliedCappConfigs(ctx, k8sClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	cappConfig := appliedConfigs[0].DeepCopy()
+	for _, override := range appliedConfigs[1:] {
+		cappConfig.Spec = MergeCappConfigSpec(cappConfig.Spec, override.Spec)
+	}
+
+	return cappConfig, nil
+}
+
+// GetAppliedCappConfigs returns the CappConfigs which apply to the Capps in the given namespace, in the order
+// they are applied: the cluster CappConfig followed by the CappConfigs that select the namespace.
+func GetAppliedCappConfigs(ctx context.Context, k8sClient client.Client, namespace string) ([]cappv1alpha1.CappConfig, error) {
+	cappConfig, err := GetCappConfig(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := GetCappConfigOverrides(ctx, k8sClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]cappv1alpha1.CappConfig{*cappConfig}, overrides...), nil
+}
+
+// GetCappConfigOverrides returns the CappConfigs in the namespace of the operator whose namespace selector
+// selects the given namespace, sorted by their names. CappConfigs with an invalid namespace selector are skipped,
+// since the CappConfig controller reports them as invalid.
+func GetCappConfigOverrides(ctx context.Context, k8sClient client.Client, namespace string) ([]cappv1alpha1.CappConfig, error) {
+	ns := corev1.Namespace{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil && !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("could not fetch namespace %q: %w", namespace, err)
+	}
+
+	cappConfigs := cappv1alpha1.CappConfigList{}
+	if err := k8sClient.List(ctx, &cappConfigs, client.InNamespace(CappNS)); err != nil {
+		return nil, fmt.Errorf("could not list CappConfigs in namespace %q: %w", CappNS, err)
+	}
+
+	var overrides []cappv1alpha1.CappConfig
+	for _, cappConfig := range cappConfigs.Items {
+		if cappConfig.Name == CappConfigName || cappConfig.Spec.NamespaceSelector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(cappConfig.Spec.NamespaceSelector)
+		if err != nil {
+			log.FromContext(ctx).Error(err, fmt.Sprintf("skipping CappConfig %q with an invalid namespace selector", cappConfig.Name))
+			continue
+		}
+
+		if selector.Matches(labels.Set(ns.Labels)) {
+			overrides = append(overrides, cappConfig)
+		}
+	}
+
+	slices.SortFunc(overrides, func(a, b cappv1alpha1.CappConfig) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return overrides, nil
+}
+
+// MergeCappConfigSpec returns the given base spec with every field which is set in the override replacing its value.
+// Default resources are merged per resource name.
+func MergeCappConfigSpec(base, override cappv1alpha1.CappConfigSpec) cappv1alpha1.CappConfigSpec {
+	merged := *base.DeepCopy()
+	override = *override.DeepCopy()
+
+	mergeValue(&merged.DNSConfig.Zone, override.DNSConfig.Zone)
+	mergeValue(&merged.DNSConfig.CNAME, override.DNSConfig.CNAME)
+	mergeValue(&merged.DNSConfig.Provider, override.DNSConfig.Provider)
+	mergeValue(&merged.DNSConfig.Issuer, override.DNSConfig.Issuer)
+
+	mergeValue(&merged.AutoscaleConfig.RPS, override.AutoscaleConfig.RPS)
+	mergeValue(&merged.AutoscaleConfig.CPU, override.AutoscaleConfig.CPU)
+	mergeValue(&merged.AutoscaleConfig.Memory, override.AutoscaleConfig.Memory)
+	mergeValue(&merged.AutoscaleConfig.Concurrency, override.AutoscaleConfig.Concurrency)
+	mergeValue(&merged.AutoscaleConfig.ActivationScale, override.AutoscaleConfig.ActivationScale)
+
+	mergeValue(&merged.LogConfig.DefaultIndex, override.LogConfig.DefaultIndex)
+
+	merged.DefaultResources.Requests = mergeResourceList(merged.DefaultResources.Requests, override.DefaultResources.Requests)
+	merged.DefaultResources.Limits = mergeResourceList(merged.DefaultResources.Limits, override.DefaultResources.Limits)
+
+	if len(override.AllowedHostnamePatterns) > 0 {
+		merged.AllowedHostnamePatterns = override.AllowedHostnamePatterns
+	}
+
+	if override.RevisionHistory != nil {
+		if merged.RevisionHistory == nil {
+			merged.RevisionHistory = &cappv1alpha1.RevisionHistoryConfig{}
+		}
+		if override.RevisionHistory.Limit != nil {
+			merged.RevisionHistory.Limit = override.RevisionHistory.Limit
+		}
+		if override.RevisionHistory.MaxAge != nil {
+			merged.RevisionHistory.MaxAge = override.RevisionHistory.MaxAge
+		}
+	}
+
+	return merged
+}
+
+// mergeValue sets the given field to the override value, unless the override value is the zero value.
+func mergeValue[T comparable](field *T, override T) {
+	var zero T
+	if override != zero {
+		*field = override
+	}
+}
+
+// mergeResourceList returns the base resource list with the quantities of the override list replacing its values.
+func mergeResourceList(base, override corev1.ResourceList) corev1.ResourceList {
+	if len(override) == 0 {
+		return base
+	}
+
+	merged := corev1.ResourceList{}
+	for name, quantity := range base {
+		merged[name] = quantity
+	}
+	for name, quantity := range override {
+		merged[name] = quantity
+	}
+
+	return merged
+}
@@ -0,0 +1,164 @@
+package utils_test
+
+import (
+	"context"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newClusterCappConfigSpec() cappv1alpha1.CappConfigSpec {
+	return cappv1alpha1.CappConfigSpec{
+		DNSConfig: cappv1alpha1.DNSConfig{
+			Zone:     "cluster.dev.",
+			CNAME:    "ingress.cluster.dev.",
+			Provider: "dns-default",
+			Issuer:   "cert-issuer",
+		},
+		AutoscaleConfig: cappv1alpha1.AutoscaleConfig{RPS: 200, CPU: 80, Memory: 70, Concurrency: 10, ActivationScale: 3},
+		LogConfig:       cappv1alpha1.LogConfig{DefaultIndex: "{{ .Namespace }}-{{ .Date }}"},
+		DefaultResources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		},
+		AllowedHostnamePatterns: []string{".*"},
+	}
+}
+
+func TestMergeCappConfigSpec(t *testing.T) {
+	override := cappv1alpha1.CappConfigSpec{
+		DNSConfig:       cappv1alpha1.DNSConfig{Zone: "tenant.dev.", Issuer: "tenant-issuer"},
+		AutoscaleConfig: cappv1alpha1.AutoscaleConfig{CPU: 50},
+		LogConfig:       cappv1alpha1.LogConfig{DefaultIndex: "tenant-{{ .Date }}"},
+		DefaultResources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+	}
+
+	expected := newClusterCappConfigSpec()
+	expected.DNSConfig.Zone = "tenant.dev."
+	expected.DNSConfig.Issuer = "tenant-issuer"
+	expected.AutoscaleConfig.CPU = 50
+	expected.LogConfig.DefaultIndex = "tenant-{{ .Date }}"
+	expected.DefaultResources.Requests[corev1.ResourceMemory] = resource.MustParse("256Mi")
+
+	base := newClusterCappConfigSpec()
+	assert.Equal(t, expected, utils.MergeCappConfigSpec(base, override))
+	assert.Equal(t, newClusterCappConfigSpec(), base, "Expected the base spec not to be modified")
+	assert.Equal(t, base, utils.MergeCappConfigSpec(base, cappv1alpha1.CappConfigSpec{}), "Expected an empty override to keep the base spec")
+}
+
+func TestResolveCappConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+
+	clusterConfig := &cappv1alpha1.CappConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.CappConfigName, Namespace: utils.CappNS},
+		Spec:       newClusterCappConfigSpec(),
+	}
+	tenantConfig := &cappv1alpha1.CappConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-tenant", Namespace: utils.CappNS},
+		Spec: cappv1alpha1.CappConfigSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}},
+			DNSConfig:         cappv1alpha1.DNSConfig{Zone: "tenant.dev.", Issuer: "tenant-issuer"},
+		},
+	}
+	laterTenantConfig := &cappv1alpha1.CappConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "b-tenant", Namespace: utils.CappNS},
+		Spec: cappv1alpha1.CappConfigSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}},
+			DNSConfig:         cappv1alpha1.DNSConfig{Issuer: "other-issuer"},
+		},
+	}
+	invalidConfig := &cappv1alpha1.CappConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid-tenant", Namespace: utils.CappNS},
+		Spec: cappv1alpha1.CappConfigSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "tenant", Operator: "Unknown", Values: []string{"a"}},
+			}},
+			DNSConfig: cappv1alpha1.DNSConfig{Zone: "invalid.dev."},
+		},
+	}
+	tenantNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-ns", Labels: map[string]string{"tenant": "a"}}}
+	otherNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other-ns"}}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(clusterConfig, tenantConfig, laterTenantConfig, invalidConfig, tenantNamespace, otherNamespace).Build()
+
+	tests := []struct {
+		name            string
+		namespace       string
+		expected        cappv1alpha1.DNSConfig
+		expectedApplied []string
+	}{
+		{
+			name:            "Namespace without overrides skips the invalid override",
+			namespace:       "other-ns",
+			expected:        newClusterCappConfigSpec().DNSConfig,
+			expectedApplied: []string{utils.CappConfigName},
+		},
+		{
+			name:      "Overrides applied in the order of their names, skipping the invalid override",
+			namespace: "tenant-ns",
+			expected: cappv1alpha1.DNSConfig{
+				Zone:     "tenant.dev.",
+				CNAME:    "ingress.cluster.dev.",
+				Provider: "dns-default",
+				Issuer:   "other-issuer",
+			},
+			expectedApplied: []string{utils.CappConfigName, "a-tenant", "b-tenant"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cappConfig, err := utils.ResolveCappConfig(context.Background(), k8sClient, tt.namespace)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cappConfig.Spec.DNSConfig)
+
+			appliedConfigs, err := utils.GetAppliedCappConfigs(context.Background(), k8sClient, tt.namespace)
+			assert.NoError(t, err)
+			var applied []string
+			for _, appliedConfig := range appliedConfigs {
+				applied = append(applied, appliedConfig.Name)
+			}
+			assert.Equal(t, tt.expectedApplied, applied)
+		})
+	}
+}
+
+func TestGetCappConfigCustomLocation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+
+	defaultConfig := &cappv1alpha1.CappConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.DefaultCappConfigName, Namespace: utils.DefaultCappNS},
+		Spec:       newClusterCappConfigSpec(),
+	}
+	customConfig := &cappv1alpha1.CappConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-config", Namespace: "custom-ns"},
+		Spec:       cappv1alpha1.CappConfigSpec{DNSConfig: cappv1alpha1.DNSConfig{Zone: "custom.dev."}},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(defaultConfig, customConfig).Build()
+
+	t.Cleanup(func() {
+		utils.CappNS = utils.DefaultCappNS
+		utils.CappConfigName = utils.DefaultCappConfigName
+	})
+	utils.CappNS = "custom-ns"
+	utils.CappConfigName = "custom-config"
+
+	cappConfig, err := utils.GetCappConfig(context.Background(), k8sClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "custom.dev.", cappConfig.Spec.DNSConfig.Zone)
+}
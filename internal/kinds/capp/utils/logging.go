@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+)
+
+// GetLogDestinations returns the destinations which the logs of the given Capp are shipped to.
+// The LogSpec of the Capp, when set, is returned first as an unnamed destination.
+func GetLogDestinations(capp cappv1alpha1.Capp) []cappv1alpha1.LogDestination {
+	var destinations []cappv1alpha1.LogDestination
+	if capp.Spec.LogSpec != (cappv1alpha1.LogSpec{}) {
+		destinations = append(destinations, cappv1alpha1.LogDestination{LogSpec: capp.Spec.LogSpec})
+	}
+
+	return append(destinations, capp.Spec.LogDestinations...)
+}
+
+// GetLogDestinationResourceName returns the name of the SyslogNGFlow and SyslogNGOutput of the log destination
+// with the given name. The resources of the unnamed destination are named after the Capp.
+func GetLogDestinationResourceName(cappName, destinationName string) string {
+	if destinationName == "" {
+		return cappName
+	}
+
+	return fmt.Sprintf("%s-%s", cappName, destinationName)
+}
+
+// The date placeholders of the log index are syslog-ng macros, so that the date is the one of each shipped event.
+const (
+	logIndexYear  = "${YEAR}"
+	logIndexMonth = "${MONTH}"
+	logIndexDay   = "${DAY}"
+)
+
+// LogIndexData holds the values of the placeholders of a log index template.
+type LogIndexData struct {
+	Namespace       string
+	CappName        string
+	NamespaceLabels map[string]string
+	Date            string
+	Year            string
+	Month           string
+	Day             string
+}
+
+// NewLogIndexData returns the values of the placeholders of the log index of the given Capp,
+// in a namespace with the given labels.
+func NewLogIndexData(capp cappv1alpha1.Capp, namespaceLabels map[string]string) LogIndexData {
+	if namespaceLabels == nil {
+		namespaceLabels = map[string]string{}
+	}
+
+	return LogIndexData{
+		Namespace:       capp.Namespace,
+		CappName:        capp.Name,
+		NamespaceLabels: namespaceLabels,
+		Date:            fmt.Sprintf("%s.%s.%s", logIndexYear, logIndexMonth, logIndexDay),
+		Year:            logIndexYear,
+		Month:           logIndexMonth,
+		Day:             logIndexDay,
+	}
+}
+
+// ParseLogIndex parses the given log index as a template. Missing namespace labels are errors, and the
+// lower function is available to lowercase label values, since Elasticsearch index names must be lowercase.
+func ParseLogIndex(index string) (*template.Template, error) {
+	return template.New("index").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"lower": strings.ToLower}).
+		Parse(index)
+}
+
+// ResolveLogIndex returns the given log index with its placeholders replaced by the given values.
+func ResolveLogIndex(index string, data LogIndexData) (string, error) {
+	indexTemplate, err := ParseLogIndex(index)
+	if err != nil {
+		return "", fmt.Errorf("invalid index %q: %w", index, err)
+	}
+
+	var resolved bytes.Buffer
+	if err := indexTemplate.Execute(&resolved, data); err != nil {
+		return "", fmt.Errorf("failed to resolve index %q: %w", index, err)
+	}
+
+	return resolved.String(), nil
+}
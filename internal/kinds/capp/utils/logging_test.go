@@ -0,0 +1,84 @@
+package utils_test
+
+import (
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetLogDestinations(t *testing.T) {
+	logSpec := cappv1alpha1.LogSpec{Type: "elastic", Host: "https://elastic:9200", Index: "main", User: "elastic", PasswordSecret: "es-secret"}
+	security := cappv1alpha1.LogDestination{Name: "security", LogSpec: cappv1alpha1.LogSpec{Type: "loki", Host: "loki:9095"}}
+
+	capp := cappv1alpha1.Capp{}
+	assert.Empty(t, utils.GetLogDestinations(capp))
+
+	capp.Spec.LogDestinations = []cappv1alpha1.LogDestination{security}
+	assert.Equal(t, []cappv1alpha1.LogDestination{security}, utils.GetLogDestinations(capp))
+
+	capp.Spec.LogSpec = logSpec
+	assert.Equal(t, []cappv1alpha1.LogDestination{{LogSpec: logSpec}, security}, utils.GetLogDestinations(capp))
+}
+
+func TestGetLogDestinationResourceName(t *testing.T) {
+	assert.Equal(t, "test-capp", utils.GetLogDestinationResourceName("test-capp", ""))
+	assert.Equal(t, "test-capp-security", utils.GetLogDestinationResourceName("test-capp", "security"))
+}
+
+func TestResolveLogIndex(t *testing.T) {
+	capp := cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"}}
+	data := utils.NewLogIndexData(capp, map[string]string{"team": "Payments"})
+
+	tests := []struct {
+		name          string
+		index         string
+		expected      string
+		errorContains string
+	}{
+		{
+			name:     "Literal index",
+			index:    "main",
+			expected: "main",
+		},
+		{
+			name:     "Namespace and Capp name",
+			index:    "{{ .Namespace }}-{{ .CappName }}",
+			expected: "test-ns-test-capp",
+		},
+		{
+			name:     "Lowercased namespace label and date",
+			index:    "{{ .NamespaceLabels.team | lower }}-{{ .Date }}",
+			expected: "payments-${YEAR}.${MONTH}.${DAY}",
+		},
+		{
+			name:     "Date parts",
+			index:    "{{ .Namespace }}-{{ .Year }}-{{ .Month }}",
+			expected: "test-ns-${YEAR}-${MONTH}",
+		},
+		{
+			name:          "Missing namespace label",
+			index:         "{{ .NamespaceLabels.tenant }}",
+			errorContains: "failed to resolve index",
+		},
+		{
+			name:          "Invalid template",
+			index:         "{{ .Namespace",
+			errorContains: "invalid index",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, err := utils.ResolveLogIndex(tt.index, data)
+			if tt.errorContains != "" {
+				assert.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, index)
+		})
+	}
+}
@@ -11,13 +11,16 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/types"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	dot                 = "."
 	maxCommonNameLength = 64
+	latestPreviewTag    = "latest"
 )
 
 // IsDNSRecordAvailable returns a boolean indicating whether a CNAMERecord is currently available.
@@ -37,11 +40,11 @@ func IsDNSRecordAvailable(ctx context.Context, k8sClient client.Client, name, na
 	return available, nil
 }
 
-// GetDNSConfig returns the data of the DNS for the CappConfig CRD.
-func GetDNSConfig(ctx context.Context, k8sClient client.Client) (cappv1alpha1.DNSConfig, error) {
-	cappConfig := cappv1alpha1.CappConfig{}
-	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: CappNS, Name: CappConfigName}, &cappConfig); err != nil {
-		return cappv1alpha1.DNSConfig{}, fmt.Errorf("could not fetch cappConfig %q from namespace %q: %w", CappConfigName, CappNS, err)
+// GetDNSConfig returns the data of the DNS for the CappConfig CRD of the Capps in the given namespace.
+func GetDNSConfig(ctx context.Context, k8sClient client.Client, namespace string) (cappv1alpha1.DNSConfig, error) {
+	cappConfig, err := ResolveCappConfig(ctx, k8sClient, namespace)
+	if err != nil {
+		return cappv1alpha1.DNSConfig{}, fmt.Errorf("could not resolve cappConfig %q from namespace %q: %w", CappConfigName, CappNS, err)
 	}
 	return cappConfig.Spec.DNSConfig, nil
 }
@@ -126,3 +129,118 @@ func TruncateCommonName(s string) string {
 	}
 	return s
 }
+
+// GetTrafficTargets returns the routing table of the Capp route. TrafficTargets takes precedence over
+// the single TrafficTarget. If neither is set, nil is returned so that Knative routes all traffic to the latest revision.
+// Untagged targets which receive no traffic are given a generated tag, so that they are exposed on a preview URL.
+func GetTrafficTargets(routeSpec cappv1alpha1.RouteSpec) []knativev1.TrafficTarget {
+	if len(routeSpec.TrafficTargets) > 0 {
+		trafficTargets := make([]knativev1.TrafficTarget, len(routeSpec.TrafficTargets))
+		for i := range routeSpec.TrafficTargets {
+			routeSpec.TrafficTargets[i].DeepCopyInto(&trafficTargets[i])
+			if trafficTargets[i].Tag == "" && !hasTraffic(trafficTargets[i]) {
+				trafficTargets[i].Tag = GeneratePreviewTag(trafficTargets[i])
+			}
+		}
+		return trafficTargets
+	}
+
+	if !equality.Semantic.DeepEqual(routeSpec.TrafficTarget, knativev1.TrafficTarget{}) {
+		return []knativev1.TrafficTarget{*routeSpec.TrafficTarget.DeepCopy()}
+	}
+
+	return nil
+}
+
+// PreviewRoute is a tagged revision of the Capp which receives no traffic and is exposed on its own hostname.
+type PreviewRoute struct {
+	// Tag is the tag of the traffic target.
+	Tag string
+	// RevisionName is the name of the tagged revision, empty if the tag follows the latest revision.
+	RevisionName string
+	// Hostname is the preview hostname, in the form of <tag>-<hostname>.
+	Hostname string
+}
+
+// GeneratePreviewTag returns the tag of an untagged traffic target which receives no traffic: "latest" for the
+// latest revision, and the name of the revision otherwise.
+func GeneratePreviewTag(target knativev1.TrafficTarget) string {
+	if target.RevisionName == "" {
+		return latestPreviewTag
+	}
+	return target.RevisionName
+}
+
+// hasTraffic returns whether the traffic target receives traffic.
+func hasTraffic(target knativev1.TrafficTarget) bool {
+	return target.Percent != nil && *target.Percent > 0
+}
+
+// GetPreviewRoutes returns the tagged traffic targets of the given routing table of the Capp route which receive
+// no traffic. Previews are only exposed when a custom hostname is set.
+func GetPreviewRoutes(routeSpec cappv1alpha1.RouteSpec, trafficTargets []knativev1.TrafficTarget) []PreviewRoute {
+	if !IsCustomHostnameSet(routeSpec.Hostname) {
+		return nil
+	}
+
+	var previewRoutes []PreviewRoute
+	for _, target := range trafficTargets {
+		if target.Tag == "" || hasTraffic(target) {
+			continue
+		}
+
+		previewRoutes = append(previewRoutes, PreviewRoute{
+			Tag:          target.Tag,
+			RevisionName: target.RevisionName,
+			Hostname:     GeneratePreviewHostname(target.Tag, routeSpec.Hostname),
+		})
+	}
+
+	return previewRoutes
+}
+
+// RouteHostname is a hostname of the Capp route.
+type RouteHostname struct {
+	// Hostname is the hostname.
+	Hostname string
+	// TlsEnabled determines whether TLS is enabled for the hostname.
+	TlsEnabled bool
+	// Tag is the tag of the revision exposed on the hostname, empty if the hostname is not a preview.
+	Tag string
+}
+
+// GetCustomHostnames returns the custom hostname of the Capp followed by its additional hostnames.
+func GetCustomHostnames(routeSpec cappv1alpha1.RouteSpec) []cappv1alpha1.HostnameSpec {
+	if !IsCustomHostnameSet(routeSpec.Hostname) {
+		return nil
+	}
+
+	hostnames := []cappv1alpha1.HostnameSpec{{Hostname: routeSpec.Hostname, TlsEnabled: routeSpec.TlsEnabled}}
+	return append(hostnames, routeSpec.AdditionalHostnames...)
+}
+
+// GetRouteHostnames returns the custom hostnames of the Capp followed by the hostnames of the previews of the given
+// routing table. Previews are exposed with the TLS setting of the custom hostname of the Capp.
+func GetRouteHostnames(routeSpec cappv1alpha1.RouteSpec, trafficTargets []knativev1.TrafficTarget) []RouteHostname {
+	var routeHostnames []RouteHostname
+	for _, hostname := range GetCustomHostnames(routeSpec) {
+		routeHostnames = append(routeHostnames, RouteHostname{Hostname: hostname.Hostname, TlsEnabled: hostname.TlsEnabled})
+	}
+
+	for _, previewRoute := range GetPreviewRoutes(routeSpec, trafficTargets) {
+		routeHostnames = append(routeHostnames, RouteHostname{Hostname: previewRoute.Hostname, TlsEnabled: routeSpec.TlsEnabled, Tag: previewRoute.Tag})
+	}
+
+	return routeHostnames
+}
+
+// GeneratePreviewHostname returns the hostname on which a tagged revision is exposed.
+func GeneratePreviewHostname(tag, hostname string) string {
+	return tag + "-" + hostname
+}
+
+// GenerateTaggedServiceName returns the name of the Kubernetes Service that Knative creates for a tag
+// of a route, according to the default tag template of Knative ("{{.Tag}}-{{.Name}}").
+func GenerateTaggedServiceName(tag, name string) string {
+	return tag + "-" + name
+}
@@ -0,0 +1,130 @@
+package utils_test
+
+import (
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestGetTrafficTargets(t *testing.T) {
+	latest := knativev1.TrafficTarget{LatestRevision: ptr.To(true), Percent: ptr.To(int64(90))}
+	pinned := knativev1.TrafficTarget{RevisionName: "app-00001", Percent: ptr.To(int64(10)), Tag: "stable"}
+
+	tests := []struct {
+		name      string
+		routeSpec cappv1alpha1.RouteSpec
+		expected  []knativev1.TrafficTarget
+	}{
+		{
+			name:      "No traffic targets",
+			routeSpec: cappv1alpha1.RouteSpec{},
+			expected:  nil,
+		},
+		{
+			name:      "Single traffic target",
+			routeSpec: cappv1alpha1.RouteSpec{TrafficTarget: latest},
+			expected:  []knativev1.TrafficTarget{latest},
+		},
+		{
+			name:      "Traffic targets take precedence",
+			routeSpec: cappv1alpha1.RouteSpec{TrafficTarget: latest, TrafficTargets: []knativev1.TrafficTarget{latest, pinned}},
+			expected:  []knativev1.TrafficTarget{latest, pinned},
+		},
+		{
+			name: "Untagged targets without traffic get a generated tag",
+			routeSpec: cappv1alpha1.RouteSpec{TrafficTargets: []knativev1.TrafficTarget{
+				{RevisionName: "app-00001", Percent: ptr.To(int64(100))},
+				{RevisionName: "app-00002", Percent: ptr.To(int64(0))},
+				{LatestRevision: ptr.To(true)},
+			}},
+			expected: []knativev1.TrafficTarget{
+				{RevisionName: "app-00001", Percent: ptr.To(int64(100))},
+				{RevisionName: "app-00002", Percent: ptr.To(int64(0)), Tag: "app-00002"},
+				{LatestRevision: ptr.To(true), Tag: "latest"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, utils.GetTrafficTargets(tt.routeSpec))
+		})
+	}
+}
+
+func TestGetPreviewRoutes(t *testing.T) {
+	trafficTargets := []knativev1.TrafficTarget{
+		{LatestRevision: ptr.To(true), Percent: ptr.To(int64(100)), Tag: "live"},
+		{RevisionName: "app-00002", Percent: ptr.To(int64(0)), Tag: "qa"},
+		{LatestRevision: ptr.To(false), RevisionName: "app-00003", Tag: "next"},
+		{RevisionName: "app-00004", Percent: ptr.To(int64(0))},
+	}
+
+	tests := []struct {
+		name              string
+		routeSpec         cappv1alpha1.RouteSpec
+		expected          []utils.PreviewRoute
+		expectedHostnames []string
+	}{
+		{
+			name:      "No custom hostname",
+			routeSpec: cappv1alpha1.RouteSpec{TrafficTargets: trafficTargets},
+		},
+		{
+			name:              "No previews",
+			routeSpec:         cappv1alpha1.RouteSpec{Hostname: "app.example.com"},
+			expectedHostnames: []string{"app.example.com"},
+		},
+		{
+			name:      "Revisions without traffic",
+			routeSpec: cappv1alpha1.RouteSpec{Hostname: "app.example.com", TrafficTargets: trafficTargets},
+			expected: []utils.PreviewRoute{
+				{Tag: "qa", RevisionName: "app-00002", Hostname: "qa-app.example.com"},
+				{Tag: "next", RevisionName: "app-00003", Hostname: "next-app.example.com"},
+				{Tag: "app-00004", RevisionName: "app-00004", Hostname: "app-00004-app.example.com"},
+			},
+			expectedHostnames: []string{"app.example.com", "qa-app.example.com", "next-app.example.com", "app-00004-app.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trafficTargets := utils.GetTrafficTargets(tt.routeSpec)
+			assert.Equal(t, tt.expected, utils.GetPreviewRoutes(tt.routeSpec, trafficTargets))
+
+			var hostnames []string
+			for _, routeHostname := range utils.GetRouteHostnames(tt.routeSpec, trafficTargets) {
+				hostnames = append(hostnames, routeHostname.Hostname)
+			}
+			assert.Equal(t, tt.expectedHostnames, hostnames)
+		})
+	}
+}
+
+func TestGetRouteHostnames(t *testing.T) {
+	routeSpec := cappv1alpha1.RouteSpec{
+		Hostname:            "app.example.com",
+		TlsEnabled:          true,
+		AdditionalHostnames: []cappv1alpha1.HostnameSpec{{Hostname: "app.legacy.com"}},
+		TrafficTargets: []knativev1.TrafficTarget{
+			{LatestRevision: ptr.To(true), Percent: ptr.To(int64(100))},
+			{RevisionName: "app-00002", Percent: ptr.To(int64(0)), Tag: "qa"},
+		},
+	}
+
+	expected := []utils.RouteHostname{
+		{Hostname: "app.example.com", TlsEnabled: true},
+		{Hostname: "app.legacy.com"},
+		{Hostname: "qa-app.example.com", TlsEnabled: true, Tag: "qa"},
+	}
+
+	assert.Equal(t, expected, utils.GetRouteHostnames(routeSpec, utils.GetTrafficTargets(routeSpec)))
+	assert.Equal(t, []cappv1alpha1.HostnameSpec{
+		{Hostname: "app.example.com", TlsEnabled: true},
+		{Hostname: "app.legacy.com"},
+	}, utils.GetCustomHostnames(routeSpec))
+}
@@ -23,9 +23,18 @@ var (
 )
 
 const (
-	CappConfigName = "capp-config"
-	CappNS         = "container-app-operator-system"
-	CappKey        = "capp"
+	DefaultCappConfigName = "capp-config"
+	DefaultCappNS         = "container-app-operator-system"
+	CappKey               = "capp"
+)
+
+var (
+	// CappNS is the namespace of the operator, in which the CappConfigs are looked up.
+	// It is set once at startup from the flags of the manager.
+	CappNS = DefaultCappNS
+
+	// CappConfigName is the name of the cluster CappConfig. It is set once at startup from the flags of the manager.
+	CappConfigName = DefaultCappConfigName
 )
 
 // IsOnOpenshift returns true if the cluster has the openshift config group
@@ -110,9 +119,9 @@ func GetResource(k8sClient client.Client, obj client.Object, name, namespace str
 }
 
 // GetCappConfig fetches and returns an existing instance of an existing cappConfig
-func GetCappConfig(k8sClient client.Client) (*cappv1alpha1.CappConfig, error) {
+func GetCappConfig(ctx context.Context, k8sClient client.Client) (*cappv1alpha1.CappConfig, error) {
 	cappConfig := &cappv1alpha1.CappConfig{}
-	if err := GetResource(k8sClient, cappConfig, CappConfigName, CappNS); err != nil {
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: CappConfigName, Namespace: CappNS}, cappConfig); err != nil {
 		return nil, err
 	}
 	return cappConfig, nil
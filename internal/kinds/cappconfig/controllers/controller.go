@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/dana-team/container-app-operator/internal/kinds/cappconfig/validation"
+	dnsv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	cappConfigControllerName = "CappConfigController"
+
+	ConditionTypeReady                   = "Ready"
+	ConditionTypeValid                   = "Valid"
+	ConditionTypeIssuerAvailable         = "IssuerAvailable"
+	ConditionTypeProviderConfigAvailable = "ProviderConfigAvailable"
+
+	ReasonReady    = "Ready"
+	ReasonNotReady = "NotReady"
+	ReasonValid    = "Valid"
+	ReasonInvalid  = "Invalid"
+	ReasonFound    = "Found"
+	ReasonNotFound = "NotFound"
+	ReasonNotSet   = "NotSet"
+)
+
+// CappConfigReconciler reconciles a CappConfig object
+type CappConfigReconciler struct {
+	Log logr.Logger
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=cappconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=cappconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=rcs.dana.io,resources=capps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="cert-manager.io",resources=clusterissuers,verbs=get;list;watch
+// +kubebuilder:rbac:groups="dns-v2.m.crossplane.io",resources=clusterproviderconfigs,verbs=get;list;watch
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CappConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	createOrDelete := predicate.Funcs{
+		UpdateFunc: func(event.UpdateEvent) bool { return false },
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&cappv1alpha1.CappConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named(cappConfigControllerName).
+		Watches(
+			&cappv1alpha1.Capp{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappConfigs),
+			builder.WithPredicates(createOrDelete),
+		).
+		Watches(
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappConfigs),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		).
+		Watches(
+			&cmapi.ClusterIssuer{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappConfigs),
+			builder.WithPredicates(createOrDelete),
+		).
+		Watches(
+			&dnsv1beta1.ClusterProviderConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.findCappConfigs),
+			builder.WithPredicates(createOrDelete),
+		).
+		Complete(r)
+}
+
+// findCappConfigs maps a change to a resource which affects the status of the CappConfigs to
+// reconciliation requests of all the CappConfigs in the namespace of the operator.
+func (r *CappConfigReconciler) findCappConfigs(ctx context.Context, _ client.Object) []reconcile.Request {
+	cappConfigs := cappv1alpha1.CappConfigList{}
+	if err := r.List(ctx, &cappConfigs, client.InNamespace(utils.CappNS)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list CappConfigs")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(cappConfigs.Items))
+	for _, cappConfig := range cappConfigs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cappConfig.Namespace, Name: cappConfig.Name}})
+	}
+
+	return requests
+}
+
+func (r *CappConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("CappConfigName", req.Name, "CappConfigNamespace", req.Namespace)
+	logger.Info("Starting Reconcile")
+
+	cappConfig := cappv1alpha1.CappConfig{}
+	if err := r.Get(ctx, req.NamespacedName, &cappConfig); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("CappConfig does not exist")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get CappConfig: %s", err.Error())
+	}
+
+	cappConfigStatus, err := r.buildStatus(ctx, cappConfig)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to build CappConfig status: %s", err.Error())
+	}
+
+	if equality.Semantic.DeepEqual(cappConfig.Status, cappConfigStatus) {
+		return ctrl.Result{}, nil
+	}
+
+	cappConfig.Status = cappConfigStatus
+	if err := r.Status().Update(ctx, &cappConfig); err != nil {
+		if errors.IsConflict(err) {
+			logger.Info(fmt.Sprintf("Conflict detected, requeuing: %s", err.Error()))
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update CappConfig status: %s", err.Error())
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// buildStatus computes the status of the CappConfig: its conditions and the number of Capps which use it.
+func (r *CappConfigReconciler) buildStatus(ctx context.Context, cappConfig cappv1alpha1.CappConfig) (cappv1alpha1.CappConfigStatus, error) {
+	cappConfigStatus := *cappConfig.Status.DeepCopy()
+
+	issuerCondition, err := buildReferenceCondition(ctx, r.Client, ConditionTypeIssuerAvailable, "ClusterIssuer",
+		cappConfig.Spec.DNSConfig.Issuer, validation.IsClusterIssuerAvailable)
+	if err != nil {
+		return cappConfigStatus, err
+	}
+
+	providerConfigCondition, err := buildReferenceCondition(ctx, r.Client, ConditionTypeProviderConfigAvailable, "ClusterProviderConfig",
+		cappConfig.Spec.DNSConfig.Provider, validation.IsProviderConfigAvailable)
+	if err != nil {
+		return cappConfigStatus, err
+	}
+
+	conditions := []metav1.Condition{buildValidCondition(cappConfig), issuerCondition, providerConfigCondition}
+	conditions = append(conditions, buildReadyCondition(conditions))
+	for _, condition := range conditions {
+		condition.ObservedGeneration = cappConfig.Generation
+		meta.SetStatusCondition(&cappConfigStatus.Conditions, condition)
+	}
+
+	capps, namespaces, err := r.countCapps(ctx, cappConfig)
+	if err != nil {
+		return cappConfigStatus, err
+	}
+
+	cappConfigStatus.Capps = capps
+	cappConfigStatus.Namespaces = namespaces
+	cappConfigStatus.ObservedGeneration = cappConfig.Generation
+
+	return cappConfigStatus, nil
+}
+
+// buildValidCondition returns the Valid condition of the CappConfig.
+func buildValidCondition(cappConfig cappv1alpha1.CappConfig) metav1.Condition {
+	if errs := validation.ValidateSpec(cappConfig); errs != nil {
+		return metav1.Condition{Type: ConditionTypeValid, Status: metav1.ConditionFalse, Reason: ReasonInvalid, Message: errs.Error()}
+	}
+
+	return metav1.Condition{Type: ConditionTypeValid, Status: metav1.ConditionTrue, Reason: ReasonValid}
+}
+
+// buildReferenceCondition returns a condition reporting whether the referenced cluster-scoped resource of the given
+// kind exists. A reference which is not set is not reported as missing, since an override inherits it.
+func buildReferenceCondition(ctx context.Context, k8sClient client.Client, conditionType, kind, name string,
+	isAvailable func(context.Context, client.Client, string) (bool, error)) (metav1.Condition, error) {
+	if name == "" {
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue, Reason: ReasonNotSet,
+			Message: fmt.Sprintf("No %s is set", kind)}, nil
+	}
+
+	available, err := isAvailable(ctx, k8sClient, name)
+	if err != nil {
+		return metav1.Condition{}, err
+	}
+
+	if !available {
+		return metav1.Condition{Type: conditionType, Status: metav1.ConditionFalse, Reason: ReasonNotFound,
+			Message: fmt.Sprintf("%s %q does not exist", kind, name)}, nil
+	}
+
+	return metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue, Reason: ReasonFound,
+		Message: fmt.Sprintf("%s %q exists", kind, name)}, nil
+}
+
+// buildReadyCondition combines the given conditions into the Ready condition.
+func buildReadyCondition(conditions []metav1.Condition) metav1.Condition {
+	var messages []string
+	for _, condition := range conditions {
+		if condition.Status != metav1.ConditionTrue {
+			messages = append(messages, condition.Message)
+		}
+	}
+
+	if len(messages) > 0 {
+		return metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: ReasonNotReady, Message: strings.Join(messages, "; ")}
+	}
+
+	return metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: ReasonReady}
+}
+
+// countCapps returns the number of Capps which use the CappConfig, and the number of namespaces they are in.
+// The cluster CappConfig is used by all the Capps, while an override is used by the Capps in the namespaces
+// it selects. Other CappConfigs are not used.
+func (r *CappConfigReconciler) countCapps(ctx context.Context, cappConfig cappv1alpha1.CappConfig) (int, int, error) {
+	selector := labels.Everything()
+	if !validation.IsClusterConfig(cappConfig) {
+		if cappConfig.Namespace != utils.CappNS || cappConfig.Spec.NamespaceSelector == nil {
+			return 0, 0, nil
+		}
+
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(cappConfig.Spec.NamespaceSelector)
+		if err != nil {
+			return 0, 0, nil
+		}
+	}
+
+	namespaceList := corev1.NamespaceList{}
+	if err := r.List(ctx, &namespaceList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return 0, 0, err
+	}
+
+	selectedNamespaces := map[string]bool{}
+	for _, namespace := range namespaceList.Items {
+		selectedNamespaces[namespace.Name] = true
+	}
+
+	cappList := cappv1alpha1.CappList{}
+	if err := r.List(ctx, &cappList); err != nil {
+		return 0, 0, err
+	}
+
+	capps := 0
+	namespaces := map[string]bool{}
+	for _, capp := range cappList.Items {
+		if selectedNamespaces[capp.Namespace] {
+			capps++
+			namespaces[capp.Namespace] = true
+		}
+	}
+
+	return capps, len(namespaces), nil
+}
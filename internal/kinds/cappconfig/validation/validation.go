@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	dnsv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const dot = "."
+
+// IsClusterConfig returns whether the given CappConfig is the cluster CappConfig, which applies to all the Capps.
+func IsClusterConfig(cappConfig cappv1alpha1.CappConfig) bool {
+	return cappConfig.Name == utils.CappConfigName && cappConfig.Namespace == utils.CappNS
+}
+
+// ValidateSpec validates the values of the given CappConfig. The DNS config of the cluster CappConfig must be
+// complete, while an override only validates the values it sets.
+func ValidateSpec(cappConfig cappv1alpha1.CappConfig) (errs *apis.FieldError) {
+	spec := cappConfig.Spec
+
+	if spec.NamespaceSelector != nil {
+		if IsClusterConfig(cappConfig) {
+			errs = errs.Also(apis.ErrDisallowedFields("namespaceSelector"))
+		} else if _, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), "namespaceSelector"))
+		}
+	}
+
+	if IsClusterConfig(cappConfig) {
+		errs = errs.Also(validateRequiredDNSConfig(spec.DNSConfig).ViaField("dnsConfig"))
+	}
+
+	if spec.DNSConfig.Zone != "" && !strings.HasSuffix(spec.DNSConfig.Zone, dot) {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s: zone must end with a %q", spec.DNSConfig.Zone, dot), "dnsConfig.zone"))
+	}
+
+	autoscaleValues := []struct {
+		field string
+		value int
+	}{
+		{field: "rps", value: spec.AutoscaleConfig.RPS},
+		{field: "cpu", value: spec.AutoscaleConfig.CPU},
+		{field: "memory", value: spec.AutoscaleConfig.Memory},
+		{field: "concurrency", value: spec.AutoscaleConfig.Concurrency},
+		{field: "activationScale", value: spec.AutoscaleConfig.ActivationScale},
+	}
+	for _, autoscaleValue := range autoscaleValues {
+		if autoscaleValue.value < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(autoscaleValue.value, autoscaleValue.field).ViaField("autoscaleConfig"))
+		}
+	}
+
+	for i, pattern := range spec.AllowedHostnamePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(fmt.Sprintf("%s: %v", pattern, err), "allowedHostnamePatterns", i))
+		}
+	}
+
+	if spec.LogConfig.DefaultIndex != "" {
+		if _, err := utils.ParseLogIndex(spec.LogConfig.DefaultIndex); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s: %v", spec.LogConfig.DefaultIndex, err), "logConfig.defaultIndex"))
+		}
+	}
+
+	if spec.RevisionHistory != nil && spec.RevisionHistory.MaxAge != nil && spec.RevisionHistory.MaxAge.Duration < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(spec.RevisionHistory.MaxAge.Duration.String(), "revisionHistory.maxAge"))
+	}
+
+	return errs
+}
+
+// validateRequiredDNSConfig returns an error for every field of the DNS config which is not set.
+func validateRequiredDNSConfig(dnsConfig cappv1alpha1.DNSConfig) *apis.FieldError {
+	var missing []string
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{name: "zone", value: dnsConfig.Zone},
+		{name: "cname", value: dnsConfig.CNAME},
+		{name: "provider", value: dnsConfig.Provider},
+		{name: "issuer", value: dnsConfig.Issuer},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return apis.ErrMissingField(missing...)
+}
+
+// IsClusterIssuerAvailable returns whether the ClusterIssuer with the given name exists.
+func IsClusterIssuerAvailable(ctx context.Context, k8sClient client.Client, name string) (bool, error) {
+	return exists(ctx, k8sClient, &cmapi.ClusterIssuer{}, name)
+}
+
+// IsProviderConfigAvailable returns whether the Crossplane DNS ClusterProviderConfig with the given name exists.
+func IsProviderConfigAvailable(ctx context.Context, k8sClient client.Client, name string) (bool, error) {
+	return exists(ctx, k8sClient, &dnsv1beta1.ClusterProviderConfig{}, name)
+}
+
+// exists returns whether the cluster-scoped object with the given name exists.
+func exists(ctx context.Context, k8sClient client.Client, object client.Object, name string) (bool, error) {
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: name}, object); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
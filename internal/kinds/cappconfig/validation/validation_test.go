@@ -0,0 +1,94 @@
+package validation_test
+
+import (
+	"testing"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/dana-team/container-app-operator/internal/kinds/cappconfig/validation"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateSpec(t *testing.T) {
+	clusterMeta := metav1.ObjectMeta{Name: utils.CappConfigName, Namespace: utils.CappNS}
+	overrideMeta := metav1.ObjectMeta{Name: "tenant", Namespace: utils.CappNS}
+	dnsConfig := cappv1alpha1.DNSConfig{Zone: "cluster.dev.", CNAME: "ingress.cluster.dev.", Provider: "dns-default", Issuer: "cert-issuer"}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}}
+
+	tests := []struct {
+		name        string
+		cappConfig  cappv1alpha1.CappConfig
+		expectedErr string
+	}{
+		{
+			name:       "Valid cluster CappConfig",
+			cappConfig: cappv1alpha1.CappConfig{ObjectMeta: clusterMeta, Spec: cappv1alpha1.CappConfigSpec{DNSConfig: dnsConfig}},
+		},
+		{
+			name:        "Cluster CappConfig with missing DNS config",
+			cappConfig:  cappv1alpha1.CappConfig{ObjectMeta: clusterMeta, Spec: cappv1alpha1.CappConfigSpec{DNSConfig: cappv1alpha1.DNSConfig{Zone: "cluster.dev."}}},
+			expectedErr: "missing field(s): dnsConfig.cname, dnsConfig.issuer, dnsConfig.provider",
+		},
+		{
+			name: "Cluster CappConfig with namespace selector",
+			cappConfig: cappv1alpha1.CappConfig{ObjectMeta: clusterMeta,
+				Spec: cappv1alpha1.CappConfigSpec{DNSConfig: dnsConfig, NamespaceSelector: selector}},
+			expectedErr: "must not set the field(s): namespaceSelector",
+		},
+		{
+			name: "Valid override",
+			cappConfig: cappv1alpha1.CappConfig{ObjectMeta: overrideMeta,
+				Spec: cappv1alpha1.CappConfigSpec{NamespaceSelector: selector, DNSConfig: cappv1alpha1.DNSConfig{Issuer: "tenant-issuer"}}},
+		},
+		{
+			name: "Zone without trailing dot",
+			cappConfig: cappv1alpha1.CappConfig{ObjectMeta: overrideMeta,
+				Spec: cappv1alpha1.CappConfigSpec{NamespaceSelector: selector, DNSConfig: cappv1alpha1.DNSConfig{Zone: "tenant.dev"}}},
+			expectedErr: "dnsConfig.zone",
+		},
+		{
+			name: "Negative autoscale value",
+			cappConfig: cappv1alpha1.CappConfig{ObjectMeta: overrideMeta,
+				Spec: cappv1alpha1.CappConfigSpec{NamespaceSelector: selector, AutoscaleConfig: cappv1alpha1.AutoscaleConfig{CPU: -1}}},
+			expectedErr: "invalid value: -1: autoscaleConfig.cpu",
+		},
+		{
+			name: "Invalid hostname pattern",
+			cappConfig: cappv1alpha1.CappConfig{ObjectMeta: overrideMeta,
+				Spec: cappv1alpha1.CappConfigSpec{NamespaceSelector: selector, AllowedHostnamePatterns: []string{".*", "("}}},
+			expectedErr: "allowedHostnamePatterns[1]",
+		},
+		{
+			name: "Negative revision history max age",
+			cappConfig: cappv1alpha1.CappConfig{ObjectMeta: overrideMeta,
+				Spec: cappv1alpha1.CappConfigSpec{NamespaceSelector: selector,
+					RevisionHistory: &cappv1alpha1.RevisionHistoryConfig{MaxAge: &metav1.Duration{Duration: -time.Hour}}}},
+			expectedErr: "revisionHistory.maxAge",
+		},
+		{
+			name: "Valid default log index",
+			cappConfig: cappv1alpha1.CappConfig{ObjectMeta: clusterMeta,
+				Spec: cappv1alpha1.CappConfigSpec{DNSConfig: dnsConfig, LogConfig: cappv1alpha1.LogConfig{DefaultIndex: "{{ .Namespace }}-{{ .Date }}"}}},
+		},
+		{
+			name: "Invalid default log index",
+			cappConfig: cappv1alpha1.CappConfig{ObjectMeta: overrideMeta,
+				Spec: cappv1alpha1.CappConfigSpec{NamespaceSelector: selector, LogConfig: cappv1alpha1.LogConfig{DefaultIndex: "{{ .Namespace"}}},
+			expectedErr: "logConfig.defaultIndex",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validation.ValidateSpec(tt.cappConfig)
+			if tt.expectedErr == "" {
+				assert.Nil(t, errs)
+				return
+			}
+			assert.NotNil(t, errs)
+			assert.Contains(t, errs.Error(), tt.expectedErr)
+		})
+	}
+}
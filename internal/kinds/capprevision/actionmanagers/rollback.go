@@ -0,0 +1,136 @@
+package actionmanagers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	RollbackSucceeded = "Succeeded"
+	RollbackFailed    = "Failed"
+
+	eventCappRollbackSucceeded = "RollbackSucceeded"
+	eventCappRollbackFailed    = "RollbackFailed"
+)
+
+// RollbackAnnotationKey is the annotation used to request a rollback of a Capp to the CappRevision with the given revision number.
+var RollbackAnnotationKey = cappv1alpha1.GroupVersion.Group + "/rollback-to"
+
+// IsRollbackRequested returns a boolean indicating whether a rollback was requested on the Capp.
+func IsRollbackRequested(capp cappv1alpha1.Capp) bool {
+	_, ok := capp.Annotations[RollbackAnnotationKey]
+	return ok
+}
+
+// HandleCappRollback restores the spec, labels and annotations of the Capp from the requested CappRevision
+// and records the rollback as a new CappRevision. The rollback annotation is removed whether the rollback
+// succeeds or not, and the result is reported in the Capp status and in an event. A conflict is returned
+// as is, so that the rollback is retried on the latest version of the Capp.
+func HandleCappRollback(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger, eventRecorder record.EventRecorder, cappRevisions []cappv1alpha1.CappRevision) error {
+	requested := capp.Annotations[RollbackAnnotationKey]
+
+	revisionNumber, err := strconv.Atoi(requested)
+	if err != nil || revisionNumber <= 0 {
+		return failRollback(ctx, k8sClient, capp, eventRecorder, 0, fmt.Sprintf("invalid revision number %q", requested))
+	}
+
+	revision, found := findRevision(cappRevisions, revisionNumber)
+	if !found {
+		return failRollback(ctx, k8sClient, capp, eventRecorder, revisionNumber, fmt.Sprintf("CappRevision with revision number %d does not exist", revisionNumber))
+	}
+
+	original := *capp.DeepCopy()
+	capp.Spec = *revision.Spec.CappTemplate.Spec.DeepCopy()
+	capp.Labels = copyMap(revision.Spec.CappTemplate.Labels)
+	capp.Annotations = copyMap(revision.Spec.CappTemplate.Annotations)
+	delete(capp.Annotations, RollbackAnnotationKey)
+
+	logger.Info(fmt.Sprintf("Rolling back Capp to CappRevision %q", revision.Name))
+	if err := k8sClient.Update(ctx, &capp); err != nil {
+		if errors.IsConflict(err) {
+			return err
+		}
+		logger.Error(err, fmt.Sprintf("Failed to roll back Capp to CappRevision %q", revision.Name))
+		return failRollback(ctx, k8sClient, original, eventRecorder, revisionNumber, err.Error())
+	}
+
+	// the update of the Capp triggers another reconcile, which schedules the pruning of the CappRevisions by age
+	if _, err := HandleCappUpdate(ctx, k8sClient, capp, logger, cappRevisions); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Rolled back to revision %d", revisionNumber)
+	eventRecorder.Event(&capp, corev1.EventTypeNormal, eventCappRollbackSucceeded, message)
+
+	return setRollbackStatus(ctx, k8sClient, capp, cappv1alpha1.RollbackStatus{
+		RevisionNumber: revisionNumber,
+		Result:         RollbackSucceeded,
+		Message:        message,
+	})
+}
+
+// failRollback removes the rollback annotation from the Capp and reports the failure in an event and in the Capp status.
+func failRollback(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, eventRecorder record.EventRecorder, revisionNumber int, message string) error {
+	eventRecorder.Event(&capp, corev1.EventTypeWarning, eventCappRollbackFailed, fmt.Sprintf("Failed to roll back: %s", message))
+
+	delete(capp.Annotations, RollbackAnnotationKey)
+	if err := k8sClient.Update(ctx, &capp); err != nil {
+		return err
+	}
+
+	return setRollbackStatus(ctx, k8sClient, capp, cappv1alpha1.RollbackStatus{
+		RevisionNumber: revisionNumber,
+		Result:         RollbackFailed,
+		Message:        message,
+	})
+}
+
+// setRollbackStatus updates the rollback status of the Capp, retrying on conflicts with the Capp controller.
+func setRollbackStatus(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, rollbackStatus cappv1alpha1.RollbackStatus) error {
+	rollbackStatus.LastRollbackTime = metav1.Now()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cappObject := cappv1alpha1.Capp{}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&capp), &cappObject); err != nil {
+			return err
+		}
+
+		cappObject.Status.RollbackStatus = rollbackStatus
+		return k8sClient.Status().Update(ctx, &cappObject)
+	})
+}
+
+// findRevision returns the CappRevision with the given revision number, and whether it was found.
+func findRevision(cappRevisions []cappv1alpha1.CappRevision, revisionNumber int) (cappv1alpha1.CappRevision, bool) {
+	for _, revision := range cappRevisions {
+		if revision.Spec.RevisionNumber == revisionNumber {
+			return revision, true
+		}
+	}
+
+	return cappv1alpha1.CappRevision{}, false
+}
+
+// copyMap returns a copy of the given map so that the CappRevision is not modified through the Capp.
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	copied := make(map[string]string, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+
+	return copied
+}
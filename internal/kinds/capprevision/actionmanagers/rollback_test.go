@@ -0,0 +1,133 @@
+package actionmanagers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestHandleCappRollback(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+
+	revision := cappv1alpha1.CappRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-capp-1", Namespace: "test-ns", CreationTimestamp: metav1.Now()},
+		Spec: cappv1alpha1.CappRevisionSpec{
+			RevisionNumber: 1,
+			CappTemplate: cappv1alpha1.CappTemplate{
+				Spec:   cappv1alpha1.CappSpec{ScaleMetric: "cpu"},
+				Labels: map[string]string{"app": "test"},
+			},
+		},
+	}
+
+	newCapp := func(rollbackTo string) *cappv1alpha1.Capp {
+		return &cappv1alpha1.Capp{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-capp",
+				Namespace:   "test-ns",
+				Annotations: map[string]string{RollbackAnnotationKey: rollbackTo},
+			},
+			Spec: cappv1alpha1.CappSpec{ScaleMetric: "rps"},
+		}
+	}
+
+	rejectSpecChanges := interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if capp, ok := obj.(*cappv1alpha1.Capp); ok && capp.Spec.ScaleMetric == "cpu" {
+				return errors.NewForbidden(schema.GroupResource{Group: cappv1alpha1.GroupVersion.Group, Resource: "capps"}, capp.Name, fmt.Errorf("denied by webhook"))
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}
+
+	conflictOnSpecChanges := interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if capp, ok := obj.(*cappv1alpha1.Capp); ok && capp.Spec.ScaleMetric == "cpu" {
+				return errors.NewConflict(schema.GroupResource{Group: cappv1alpha1.GroupVersion.Group, Resource: "capps"}, capp.Name, fmt.Errorf("modified"))
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}
+
+	tests := []struct {
+		name               string
+		rollbackTo         string
+		interceptor        interceptor.Funcs
+		expectError        bool
+		expectedResult     string
+		expectedScale      string
+		expectedAnnotation bool
+	}{
+		{
+			name:           "Rollback to an existing revision",
+			rollbackTo:     "1",
+			expectedResult: RollbackSucceeded,
+			expectedScale:  "cpu",
+		},
+		{
+			name:           "Invalid revision number",
+			rollbackTo:     "latest",
+			expectedResult: RollbackFailed,
+			expectedScale:  "rps",
+		},
+		{
+			name:           "Missing revision",
+			rollbackTo:     "7",
+			expectedResult: RollbackFailed,
+			expectedScale:  "rps",
+		},
+		{
+			name:           "Update rejected by the webhook",
+			rollbackTo:     "1",
+			interceptor:    rejectSpecChanges,
+			expectedResult: RollbackFailed,
+			expectedScale:  "rps",
+		},
+		{
+			name:               "Conflict is retried",
+			rollbackTo:         "1",
+			interceptor:        conflictOnSpecChanges,
+			expectError:        true,
+			expectedScale:      "rps",
+			expectedAnnotation: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capp := newCapp(tt.rollbackTo)
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(capp, revision.DeepCopy()).
+				WithStatusSubresource(&cappv1alpha1.Capp{}).
+				WithInterceptorFuncs(tt.interceptor).Build()
+
+			err := HandleCappRollback(context.Background(), k8sClient, *capp, logr.Discard(), record.NewFakeRecorder(10),
+				[]cappv1alpha1.CappRevision{revision})
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			updated := cappv1alpha1.Capp{}
+			assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(capp), &updated))
+			assert.Equal(t, tt.expectedResult, updated.Status.RollbackStatus.Result)
+			assert.Equal(t, tt.expectedScale, updated.Spec.ScaleMetric)
+			_, hasAnnotation := updated.Annotations[RollbackAnnotationKey]
+			assert.Equal(t, tt.expectedAnnotation, hasAnnotation)
+		})
+	}
+}
@@ -3,29 +3,86 @@ package actionmanagers
 import (
 	"context"
 	"sort"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
 	"github.com/dana-team/container-app-operator/internal/kinds/capprevision/adapters"
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const revisionsToKeep = 10
+const defaultRevisionsToKeep = 10
 
-// splitRevisionsAtIndex splits a slice of CappRevisions into two slices:
-// one containing the elements before the specified index (exclusive),
-// and one containing the elements after the specified index (inclusive).
-// If the index is out of bounds, it adjusts to return appropriate slices.
-func splitRevisionsAtIndex(revisions []cappv1alpha1.CappRevision, index int) ([]cappv1alpha1.CappRevision, []cappv1alpha1.CappRevision) {
-	if index <= 0 {
-		return []cappv1alpha1.CappRevision{}, revisions
+// revisionHistoryPolicy is the resolved CappRevision retention policy of a Capp.
+// A zero maxAge means that CappRevisions are not pruned by age.
+type revisionHistoryPolicy struct {
+	limit  int
+	maxAge time.Duration
+}
+
+// resolveRevisionHistoryPolicy returns the retention policy of a Capp. Every field of the per-Capp
+// override takes precedence over the CappConfig default, which takes precedence over the built-in default.
+func resolveRevisionHistoryPolicy(capp cappv1alpha1.Capp, config *cappv1alpha1.RevisionHistoryConfig) revisionHistoryPolicy {
+	policy := revisionHistoryPolicy{limit: defaultRevisionsToKeep}
+
+	for _, revisionHistory := range []*cappv1alpha1.RevisionHistoryConfig{config, capp.Spec.RevisionHistory} {
+		if revisionHistory == nil {
+			continue
+		}
+		if revisionHistory.Limit != nil && *revisionHistory.Limit > 0 {
+			policy.limit = *revisionHistory.Limit
+		}
+		if revisionHistory.MaxAge != nil {
+			policy.maxAge = revisionHistory.MaxAge.Duration
+		}
 	}
-	if index >= len(revisions) {
-		return revisions, []cappv1alpha1.CappRevision{}
+
+	return policy
+}
+
+// getRevisionHistoryPolicy resolves the CappConfig of the Capp and returns the retention policy of the Capp.
+// If the CappConfig cannot be resolved, the per-Capp override and the built-in default are used.
+func getRevisionHistoryPolicy(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger) revisionHistoryPolicy {
+	cappConfig, err := utils.ResolveCappConfig(ctx, k8sClient, capp.Namespace)
+	if err != nil {
+		logger.Error(err, "could not fetch cappConfig, using the default revision history policy")
+		return resolveRevisionHistoryPolicy(capp, nil)
+	}
+
+	return resolveRevisionHistoryPolicy(capp, cappConfig.Spec.RevisionHistory)
+}
+
+// selectRevisionsToDelete returns the CappRevisions which exceed the retention policy. The revisions are expected
+// to be sorted from newest to oldest. The first keep revisions are retained unless they are older than the
+// policy's max age; the first protected revisions are always retained.
+func selectRevisionsToDelete(revisions []cappv1alpha1.CappRevision, keep, protected int, maxAge time.Duration, now time.Time) []cappv1alpha1.CappRevision {
+	var revisionsToDelete []cappv1alpha1.CappRevision
+	for i, revision := range revisions {
+		if i < protected {
+			continue
+		}
+
+		expired := maxAge > 0 && now.Sub(revision.CreationTimestamp.Time) > maxAge
+		if i >= keep || expired {
+			revisionsToDelete = append(revisionsToDelete, revision)
+		}
+	}
+
+	return revisionsToDelete
+}
+
+// deleteRevisions deletes the given CappRevisions.
+func deleteRevisions(ctx context.Context, k8sClient client.Client, logger logr.Logger, revisions []cappv1alpha1.CappRevision) error {
+	for _, revision := range revisions {
+		if err := adapters.DeleteCappRevision(ctx, k8sClient, logger, &revision); err != nil {
+			return err
+		}
 	}
-	return revisions[:index], revisions[index:]
+
+	return nil
 }
 
 // sortByCreationTime sorts a slice of CappRevision by the CreatedAt field.
@@ -59,26 +116,75 @@ func isEqual(capp cappv1alpha1.Capp, revision cappv1alpha1.CappRevision) bool {
 }
 
 // HandleCappUpdate manages the flow of CappRevision when a Capp is updated. It ensures that a CappRevision is created for every update.
-// It also maintains a limit of only revisionsToKeep CappRevisions in the same namespace as the Capp.
-func HandleCappUpdate(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger, cappRevisions []cappv1alpha1.CappRevision) error {
+// It also prunes the CappRevisions of the Capp according to its retention policy, by count or by age, whichever limit is hit first.
+// It returns the time until the next retained CappRevision exceeds the max age, or zero if none will, so that the
+// CappRevisions of a Capp which is not updated are still pruned by age.
+func HandleCappUpdate(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger, cappRevisions []cappv1alpha1.CappRevision) (time.Duration, error) {
 	sortByCreationTime(cappRevisions)
-	numOfRevisions := len(cappRevisions)
+	policy := getRevisionHistoryPolicy(ctx, k8sClient, capp, logger)
+	now := time.Now()
 
 	latestRevision := cappRevisions[0]
 	if isEqual(capp, latestRevision) {
-		return nil
+		var previousRevision *cappv1alpha1.CappRevision
+		if len(cappRevisions) > 1 {
+			previousRevision = &cappRevisions[1]
+		}
+		if err := adapters.EnsureCappRevisionStatus(ctx, k8sClient, logger, capp, &latestRevision, previousRevision); err != nil {
+			return 0, err
+		}
+
+		// the latest revision matches the current state of the Capp, so it is never pruned
+		revisionsToDelete := selectRevisionsToDelete(cappRevisions, policy.limit, 1, policy.maxAge, now)
+		if err := deleteRevisions(ctx, k8sClient, logger, revisionsToDelete); err != nil {
+			return 0, err
+		}
+		return nextExpiry(retainedRevisions(cappRevisions[1:], revisionsToDelete), policy.maxAge, now), nil
 	}
 
-	if numOfRevisions < revisionsToKeep {
-		return adapters.CreateCappRevision(ctx, k8sClient, logger, capp, cappRevisions[0].Spec.RevisionNumber+1)
+	// make room for the revision which is about to be created
+	revisionsToDelete := selectRevisionsToDelete(cappRevisions, policy.limit-1, 0, policy.maxAge, now)
+	if err := deleteRevisions(ctx, k8sClient, logger, revisionsToDelete); err != nil {
+		return 0, err
 	}
-	relevantRevision, revisionsToDelete := splitRevisionsAtIndex(cappRevisions, revisionsToKeep-1)
 
-	for _, revision := range revisionsToDelete {
-		if err := adapters.DeleteCappRevision(ctx, k8sClient, logger, &revision); err != nil {
-			return err
+	if err := adapters.CreateCappRevision(ctx, k8sClient, logger, capp, latestRevision.Spec.RevisionNumber+1, &latestRevision); err != nil {
+		return 0, err
+	}
+	return nextExpiry(retainedRevisions(cappRevisions, revisionsToDelete), policy.maxAge, now), nil
+}
+
+// retainedRevisions returns the given CappRevisions without the deleted ones.
+func retainedRevisions(revisions, deleted []cappv1alpha1.CappRevision) []cappv1alpha1.CappRevision {
+	deletedNames := map[string]bool{}
+	for _, revision := range deleted {
+		deletedNames[revision.Name] = true
+	}
+
+	var retained []cappv1alpha1.CappRevision
+	for _, revision := range revisions {
+		if !deletedNames[revision.Name] {
+			retained = append(retained, revision)
+		}
+	}
+
+	return retained
+}
+
+// nextExpiry returns the time until the first of the given CappRevisions exceeds the max age,
+// or zero if the CappRevisions are not pruned by age.
+func nextExpiry(revisions []cappv1alpha1.CappRevision, maxAge time.Duration, now time.Time) time.Duration {
+	if maxAge <= 0 {
+		return 0
+	}
+
+	var next time.Duration
+	for _, revision := range revisions {
+		expiry := max(revision.CreationTimestamp.Add(maxAge).Sub(now), time.Second)
+		if next == 0 || expiry < next {
+			next = expiry
 		}
 	}
 
-	return adapters.CreateCappRevision(ctx, k8sClient, logger, capp, relevantRevision[0].Spec.RevisionNumber+1)
+	return next
 }
@@ -0,0 +1,120 @@
+package actionmanagers
+
+import (
+	"testing"
+	"time"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestResolveRevisionHistoryPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		capp     cappv1alpha1.Capp
+		config   *cappv1alpha1.RevisionHistoryConfig
+		expected revisionHistoryPolicy
+	}{
+		{
+			name:     "Built-in default",
+			expected: revisionHistoryPolicy{limit: defaultRevisionsToKeep},
+		},
+		{
+			name:     "CappConfig default",
+			config:   &cappv1alpha1.RevisionHistoryConfig{Limit: intPtr(50), MaxAge: &metav1.Duration{Duration: time.Hour}},
+			expected: revisionHistoryPolicy{limit: 50, maxAge: time.Hour},
+		},
+		{
+			name: "Capp override takes precedence field by field",
+			capp: cappv1alpha1.Capp{Spec: cappv1alpha1.CappSpec{
+				RevisionHistory: &cappv1alpha1.RevisionHistoryConfig{Limit: intPtr(3)},
+			}},
+			config:   &cappv1alpha1.RevisionHistoryConfig{Limit: intPtr(50), MaxAge: &metav1.Duration{Duration: time.Hour}},
+			expected: revisionHistoryPolicy{limit: 3, maxAge: time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolveRevisionHistoryPolicy(tt.capp, tt.config))
+		})
+	}
+}
+
+func TestSelectRevisionsToDelete(t *testing.T) {
+	now := time.Now()
+	revisions := make([]cappv1alpha1.CappRevision, 5)
+	for i := range revisions {
+		revisions[i].Name = string(rune('a' + i))
+		revisions[i].CreationTimestamp = metav1.NewTime(now.Add(-time.Duration(i) * time.Hour))
+	}
+
+	tests := []struct {
+		name      string
+		keep      int
+		protected int
+		maxAge    time.Duration
+		expected  []string
+	}{
+		{
+			name:     "Prune by count",
+			keep:     3,
+			expected: []string{"d", "e"},
+		},
+		{
+			name:     "Prune by age",
+			keep:     10,
+			maxAge:   90 * time.Minute,
+			expected: []string{"c", "d", "e"},
+		},
+		{
+			name:     "Count limit is hit first",
+			keep:     1,
+			maxAge:   90 * time.Minute,
+			expected: []string{"b", "c", "d", "e"},
+		},
+		{
+			name:      "Protected revisions are retained",
+			keep:      10,
+			protected: 1,
+			maxAge:    time.Minute,
+			expected:  []string{"b", "c", "d", "e"},
+		},
+		{
+			name: "Nothing to prune",
+			keep: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, revision := range selectRevisionsToDelete(revisions, tt.keep, tt.protected, tt.maxAge, now) {
+				names = append(names, revision.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestNextExpiry(t *testing.T) {
+	now := time.Now()
+	revisions := []cappv1alpha1.CappRevision{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))}},
+	}
+
+	assert.Equal(t, time.Duration(0), nextExpiry(revisions, 0, now), "Expected no requeue without a max age")
+	assert.Equal(t, time.Duration(0), nextExpiry(nil, 3*time.Hour, now), "Expected no requeue without revisions to prune")
+	assert.Equal(t, time.Hour, nextExpiry(revisions, 3*time.Hour, now), "Expected a requeue when the oldest revision expires")
+	assert.Equal(t, time.Second, nextExpiry(revisions, time.Hour, now), "Expected an expired revision to be requeued right away")
+
+	retained := retainedRevisions(revisions, revisions[1:])
+	assert.Len(t, retained, 1)
+	assert.Equal(t, "a", retained[0].Name)
+}
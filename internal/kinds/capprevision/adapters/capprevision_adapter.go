@@ -2,10 +2,14 @@ package adapters
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
 	"github.com/go-logr/logr"
+	"gomodules.xyz/jsonpatch/v2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -16,8 +20,10 @@ import (
 )
 
 var (
-	domain           = cappv1alpha1.GroupVersion.Group
-	cappNameLabelKey = domain + "/cappName"
+	domain                     = cappv1alpha1.GroupVersion.Group
+	cappNameLabelKey           = domain + "/cappName"
+	lastUpdatedByAnnotationKey = domain + "/last-updated-by"
+	ChangeCauseAnnotationKey   = domain + "/change-cause"
 )
 
 const (
@@ -44,8 +50,9 @@ func GetCappRevisions(ctx context.Context, r client.Client, capp cappv1alpha1.Ca
 	return cappRevisions.Items, err
 }
 
-// CreateCappRevision initializes and creates a CappRevision.
-func CreateCappRevision(ctx context.Context, k8sClient client.Client, logger logr.Logger, capp cappv1alpha1.Capp, revisionNumber int) error {
+// CreateCappRevision initializes and creates a CappRevision. Its status records the author and the cause of the change,
+// and the diff against the given previous CappRevision, if such exists.
+func CreateCappRevision(ctx context.Context, k8sClient client.Client, logger logr.Logger, capp cappv1alpha1.Capp, revisionNumber int, previousRevision *cappv1alpha1.CappRevision) error {
 	cappRevision := cappv1alpha1.CappRevision{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
@@ -74,9 +81,98 @@ func CreateCappRevision(ctx context.Context, k8sClient client.Client, logger log
 	}
 
 	logger.Info(fmt.Sprintf("Successfully created CappRevision %q", cappRevision.Name))
+
+	return EnsureCappRevisionStatus(ctx, k8sClient, logger, capp, &cappRevision, previousRevision)
+}
+
+// EnsureCappRevisionStatus fills in the status of the given CappRevision of the Capp when it is empty. It is called
+// for the latest CappRevision on every reconcile, so that a status which could not be written right after
+// the CappRevision was created is written later.
+func EnsureCappRevisionStatus(ctx context.Context, k8sClient client.Client, logger logr.Logger, capp cappv1alpha1.Capp, cappRevision *cappv1alpha1.CappRevision, previousRevision *cappv1alpha1.CappRevision) error {
+	if !equality.Semantic.DeepEqual(cappRevision.Status, cappv1alpha1.CappRevisionStatus{}) {
+		return nil
+	}
+
+	status, err := buildCappRevisionStatus(capp, *cappRevision, previousRevision)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to compute the diff of CappRevision %q.", cappRevision.Name))
+	}
+
+	if equality.Semantic.DeepEqual(status, cappv1alpha1.CappRevisionStatus{}) {
+		return nil
+	}
+
+	cappRevision.Status = status
+	if err := k8sClient.Status().Update(ctx, cappRevision); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to update the status of CappRevision %q.", cappRevision.Name))
+		return err
+	}
+
 	return nil
 }
 
+// buildCappRevisionStatus returns the status of a new CappRevision. If the diff cannot be computed,
+// the status is returned without it alongside the error.
+func buildCappRevisionStatus(capp cappv1alpha1.Capp, cappRevision cappv1alpha1.CappRevision, previousRevision *cappv1alpha1.CappRevision) (cappv1alpha1.CappRevisionStatus, error) {
+	status := cappv1alpha1.CappRevisionStatus{
+		Author:      capp.Annotations[lastUpdatedByAnnotationKey],
+		ChangeCause: capp.Annotations[ChangeCauseAnnotationKey],
+	}
+
+	if previousRevision == nil {
+		return status, nil
+	}
+
+	status.PreviousRevisionNumber = previousRevision.Spec.RevisionNumber
+	diff, err := diffCappTemplates(previousRevision.Spec.CappTemplate, cappRevision.Spec.CappTemplate)
+	if err != nil {
+		return status, err
+	}
+	status.Diff = diff
+
+	return status, nil
+}
+
+// diffCappTemplates returns a JSON patch which transforms the previous CappTemplate into the current one.
+// The operations keep the order in which they are generated, since the removals from an array must be applied
+// from the highest index down.
+func diffCappTemplates(previous, current cappv1alpha1.CappTemplate) ([]cappv1alpha1.JSONPatchOperation, error) {
+	previousJSON, err := json.Marshal(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := jsonpatch.CreatePatch(previousJSON, currentJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make([]cappv1alpha1.JSONPatchOperation, 0, len(patch))
+	for _, operation := range patch {
+		diffOperation := cappv1alpha1.JSONPatchOperation{
+			Operation: operation.Operation,
+			Path:      operation.Path,
+		}
+
+		if operation.Value != nil {
+			value, err := json.Marshal(operation.Value)
+			if err != nil {
+				return nil, err
+			}
+			diffOperation.Value = &apiextensionsv1.JSON{Raw: value}
+		}
+
+		diff = append(diff, diffOperation)
+	}
+
+	return diff, nil
+}
+
 // DeleteCappRevision deletes a specified CappRevision and returning an error on failure.
 func DeleteCappRevision(ctx context.Context, k8sClient client.Client, logger logr.Logger, cappRevision *cappv1alpha1.CappRevision) error {
 	logger.Info(fmt.Sprintf("Trying to delete CappRevision: %q", cappRevision.Name))
@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuildCappRevisionStatus(t *testing.T) {
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				lastUpdatedByAnnotationKey: "user",
+				ChangeCauseAnnotationKey:   "disable the application",
+			},
+		},
+	}
+
+	previousRevision := cappv1alpha1.CappRevision{
+		Spec: cappv1alpha1.CappRevisionSpec{
+			RevisionNumber: 1,
+			CappTemplate: cappv1alpha1.CappTemplate{
+				Spec:   cappv1alpha1.CappSpec{State: "enabled", ScaleMetric: "cpu"},
+				Labels: map[string]string{"team": "a"},
+			},
+		},
+	}
+
+	cappRevision := cappv1alpha1.CappRevision{
+		Spec: cappv1alpha1.CappRevisionSpec{
+			RevisionNumber: 2,
+			CappTemplate: cappv1alpha1.CappTemplate{
+				Spec: cappv1alpha1.CappSpec{State: "disabled", ScaleMetric: "cpu"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		previousRevision *cappv1alpha1.CappRevision
+		expectedDiff     map[string]string
+		expectedPrevious int
+	}{
+		{
+			name: "First revision has no diff",
+		},
+		{
+			name:             "Diff against the previous revision",
+			previousRevision: &previousRevision,
+			expectedDiff: map[string]string{
+				"/cappSpec/state": "replace",
+				"/labels":         "remove",
+			},
+			expectedPrevious: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := buildCappRevisionStatus(capp, cappRevision, tt.previousRevision)
+			assert.NoError(t, err)
+			assert.Equal(t, "user", status.Author)
+			assert.Equal(t, "disable the application", status.ChangeCause)
+			assert.Equal(t, tt.expectedPrevious, status.PreviousRevisionNumber)
+
+			assert.Len(t, status.Diff, len(tt.expectedDiff))
+			for _, operation := range status.Diff {
+				assert.Equal(t, tt.expectedDiff[operation.Path], operation.Operation, "unexpected operation on %q", operation.Path)
+			}
+		})
+	}
+}
+
+func TestEnsureCappRevisionStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+
+	capp := cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-capp",
+			Namespace:   "test-ns",
+			Annotations: map[string]string{lastUpdatedByAnnotationKey: "user"},
+		},
+	}
+	previousRevision := cappv1alpha1.CappRevision{Spec: cappv1alpha1.CappRevisionSpec{RevisionNumber: 1}}
+
+	tests := []struct {
+		name             string
+		capp             cappv1alpha1.Capp
+		status           cappv1alpha1.CappRevisionStatus
+		previousRevision *cappv1alpha1.CappRevision
+		expected         cappv1alpha1.CappRevisionStatus
+	}{
+		{
+			name:             "Empty status is filled in",
+			capp:             capp,
+			previousRevision: &previousRevision,
+			expected:         cappv1alpha1.CappRevisionStatus{Author: "user", PreviousRevisionNumber: 1},
+		},
+		{
+			name:     "Existing status is kept",
+			capp:     capp,
+			status:   cappv1alpha1.CappRevisionStatus{Author: "other"},
+			expected: cappv1alpha1.CappRevisionStatus{Author: "other"},
+		},
+		{
+			name: "First revision without an author stays empty",
+			capp: cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "test-capp", Namespace: "test-ns"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(&cappv1alpha1.CappRevision{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-capp-00002", Namespace: "test-ns"},
+					Spec:       cappv1alpha1.CappRevisionSpec{RevisionNumber: 2},
+					Status:     tt.status,
+				}).
+				WithStatusSubresource(&cappv1alpha1.CappRevision{}).Build()
+
+			cappRevision := cappv1alpha1.CappRevision{}
+			assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "test-ns", Name: "test-capp-00002"}, &cappRevision))
+
+			assert.NoError(t, EnsureCappRevisionStatus(context.Background(), k8sClient, logr.Discard(), tt.capp, &cappRevision, tt.previousRevision))
+
+			updated := cappv1alpha1.CappRevision{}
+			assert.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(&cappRevision), &updated))
+			assert.Equal(t, tt.expected, updated.Status)
+		})
+	}
+}
+
+func TestDiffCappTemplatesApplies(t *testing.T) {
+	template := func(env ...string) cappv1alpha1.CappTemplate {
+		container := corev1.Container{Name: "app", Image: "app:v1"}
+		for _, name := range env {
+			container.Env = append(container.Env, corev1.EnvVar{Name: name, Value: "value"})
+		}
+
+		cappTemplate := cappv1alpha1.CappTemplate{Spec: cappv1alpha1.CappSpec{State: "enabled"}}
+		cappTemplate.Spec.ConfigurationSpec.Template.Spec.Containers = []corev1.Container{container}
+		return cappTemplate
+	}
+
+	tests := []struct {
+		name     string
+		previous cappv1alpha1.CappTemplate
+		current  cappv1alpha1.CappTemplate
+	}{
+		{
+			name:     "Array shrinks",
+			previous: template("A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L"),
+			current:  template("A"),
+		},
+		{
+			name:     "Array grows",
+			previous: template("A"),
+			current:  template("A", "B", "C"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := diffCappTemplates(tt.previous, tt.current)
+			assert.NoError(t, err)
+
+			diffJSON, err := json.Marshal(diff)
+			assert.NoError(t, err)
+			patch, err := jsonpatch.DecodePatch(diffJSON)
+			assert.NoError(t, err)
+
+			previousJSON, err := json.Marshal(tt.previous)
+			assert.NoError(t, err)
+			patchedJSON, err := patch.Apply(previousJSON)
+			assert.NoError(t, err, "Expected the diff to apply to the previous CappTemplate")
+
+			patched := cappv1alpha1.CappTemplate{}
+			assert.NoError(t, json.Unmarshal(patchedJSON, &patched))
+			assert.Equal(t, tt.current, patched)
+		})
+	}
+}
@@ -36,6 +36,7 @@ type CappRevisionReconciler struct {
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capps/finalizers,verbs=update
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capprevisions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rcs.dana.io,resources=capprevisions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;update;create;patch
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *CappRevisionReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -61,26 +62,32 @@ func (r *CappRevisionReconciler) Reconcile(ctx context.Context, req reconcile.Re
 	if !capp.DeletionTimestamp.IsZero() {
 		return ctrl.Result{}, nil
 	}
-	if err := syncCappRevision(ctx, r.Client, capp, logger); err != nil {
+	requeueAfter, err := syncCappRevision(ctx, r.Client, capp, logger, r.EventRecorder)
+	if err != nil {
 		if errors.IsConflict(err) || errors.IsAlreadyExists(err) {
 			logger.Info(fmt.Sprintf("Conflict detected requeuing: %s", err.Error()))
 			return ctrl.Result{RequeueAfter: RequeueTime}, nil
 		}
 		return ctrl.Result{}, fmt.Errorf("failed to sync Capp: %s", err.Error())
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-// syncCappRevision manages the lifecycle of CappRevisions based on the state of a Capp, handling creation, update, or deletion.
-func syncCappRevision(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger) error {
+// syncCappRevision manages the lifecycle of CappRevisions based on the state of a Capp, handling creation, update, deletion or rollback.
+// It returns the time after which the CappRevisions should be synced again to prune them by age, or zero if not needed.
+func syncCappRevision(ctx context.Context, k8sClient client.Client, capp cappv1alpha1.Capp, logger logr.Logger, eventRecorder record.EventRecorder) (time.Duration, error) {
 	cappRevisions, err := adapters.GetCappRevisions(ctx, k8sClient, capp)
 	if err != nil {
 		logger.Error(err, "could not fetch cappRevisions")
-		return err
+		return 0, err
+	}
+
+	if actionmanagers.IsRollbackRequested(capp) {
+		return 0, actionmanagers.HandleCappRollback(ctx, k8sClient, capp, logger, eventRecorder, cappRevisions)
 	}
 
 	if len(cappRevisions) == 0 {
-		return actionmanagers.HandleCappCreation(ctx, k8sClient, capp, logger)
+		return 0, actionmanagers.HandleCappCreation(ctx, k8sClient, capp, logger)
 	}
 
 	return actionmanagers.HandleCappUpdate(ctx, k8sClient, capp, logger, cappRevisions)
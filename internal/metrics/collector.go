@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	collectTimeout = 10 * time.Second
+	defaultState   = "enabled"
+)
+
+// StateCollector collects metrics of the Capps in the cluster and of their Certificates.
+// It reads the objects on every scrape, so metrics of deleted objects are never reported.
+type StateCollector struct {
+	Reader client.Reader
+	Log    logr.Logger
+}
+
+// NewStateCollector returns a StateCollector which reads objects using the given reader,
+// which is expected to be backed by the cache of the manager.
+func NewStateCollector(reader client.Reader, log logr.Logger) StateCollector {
+	return StateCollector{Reader: reader, Log: log}
+}
+
+// Describe implements prometheus.Collector.
+func (s StateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cappsDesc
+	ch <- certificateExpiryDesc
+}
+
+// Collect implements prometheus.Collector.
+func (s StateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	s.collectCapps(ctx, ch)
+	s.collectCertificates(ctx, ch)
+}
+
+// collectCapps reports the number of Capps per namespace and state.
+func (s StateCollector) collectCapps(ctx context.Context, ch chan<- prometheus.Metric) {
+	cappList := cappv1alpha1.CappList{}
+	if err := s.Reader.List(ctx, &cappList); err != nil {
+		s.Log.Error(err, "failed to list Capps for metrics")
+		return
+	}
+
+	type cappStateKey struct {
+		namespace string
+		state     string
+	}
+
+	counts := map[cappStateKey]int{}
+	for _, capp := range cappList.Items {
+		state := capp.Spec.State
+		if state == "" {
+			state = defaultState
+		}
+		counts[cappStateKey{namespace: capp.Namespace, state: state}]++
+	}
+
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(cappsDesc, prometheus.GaugeValue, float64(count), key.namespace, key.state)
+	}
+}
+
+// collectCertificates reports the expiration time of every issued Certificate managed by a Capp.
+func (s StateCollector) collectCertificates(ctx context.Context, ch chan<- prometheus.Metric) {
+	certificateList := cmapi.CertificateList{}
+	if err := s.Reader.List(ctx, &certificateList, client.MatchingLabels{utils.ManagedByLabelKey: utils.CappKey}); err != nil {
+		s.Log.Error(err, "failed to list Certificates for metrics")
+		return
+	}
+
+	for _, certificate := range certificateList.Items {
+		if certificate.Status.NotAfter == nil || len(certificate.Spec.DNSNames) == 0 {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(certificateExpiryDesc, prometheus.GaugeValue,
+			float64(certificate.Status.NotAfter.Unix()),
+			certificate.Namespace, certificate.Labels[utils.CappResourceKey], certificate.Spec.DNSNames[0],
+		)
+	}
+}
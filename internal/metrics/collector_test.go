@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestCapp(name, namespace, state string) *cappv1alpha1.Capp {
+	return &cappv1alpha1.Capp{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       cappv1alpha1.CappSpec{State: state},
+	}
+}
+
+func newTestCertificate(name, cappName, dnsName string, notAfter *metav1.Time, labels map[string]string) *cmapi.Certificate {
+	certificate := &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns", Labels: labels},
+		Spec:       cmapi.CertificateSpec{DNSNames: []string{dnsName}},
+	}
+	certificate.Labels[utils.CappResourceKey] = cappName
+	certificate.Status.NotAfter = notAfter
+	return certificate
+}
+
+func TestStateCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, cappv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, cmapi.AddToScheme(scheme))
+
+	notAfter := metav1.NewTime(time.Unix(1800000000, 0))
+	objects := []client.Object{
+		newTestCapp("first", "test-ns", "enabled"),
+		newTestCapp("second", "test-ns", ""),
+		newTestCapp("third", "test-ns", "disabled"),
+		newTestCapp("fourth", "other-ns", "enabled"),
+		newTestCertificate("issued", "first", "first.example.com", &notAfter, map[string]string{utils.ManagedByLabelKey: utils.CappKey}),
+		newTestCertificate("pending", "second", "second.example.com", nil, map[string]string{utils.ManagedByLabelKey: utils.CappKey}),
+		newTestCertificate("unmanaged", "third", "third.example.com", &notAfter, map[string]string{}),
+	}
+
+	collector := NewStateCollector(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(), logr.Discard())
+
+	expected := `
+# HELP capp_capps Number of Capps, per namespace and state.
+# TYPE capp_capps gauge
+capp_capps{namespace="other-ns",state="enabled"} 1
+capp_capps{namespace="test-ns",state="disabled"} 1
+capp_capps{namespace="test-ns",state="enabled"} 2
+# HELP capp_certificate_expiration_timestamp_seconds Expiration time of the certificate of a Capp hostname, in seconds since the epoch.
+# TYPE capp_certificate_expiration_timestamp_seconds gauge
+capp_certificate_expiration_timestamp_seconds{capp="first",hostname="first.example.com",namespace="test-ns"} 1.8e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
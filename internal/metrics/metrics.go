@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "capp"
+
+	labelManager   = "manager"
+	labelWebhook   = "webhook"
+	labelResult    = "result"
+	labelReason    = "reason"
+	labelState     = "state"
+	labelNamespace = "namespace"
+	labelCapp      = "capp"
+	labelHostname  = "hostname"
+	labelKind      = "kind"
+
+	ResultAllowed = "allowed"
+	ResultDenied  = "denied"
+	ResultErrored = "errored"
+)
+
+var (
+	// ResourceManagerDuration is the time it takes a resource manager to manage the resources of a Capp.
+	ResourceManagerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "resource_manager_reconcile_duration_seconds",
+		Help:      "Duration of the reconciliation of the resources of a Capp, per resource manager.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{labelManager})
+
+	// ResourceManagerErrors is the number of failed reconciliations of a resource manager.
+	ResourceManagerErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "resource_manager_reconcile_errors_total",
+		Help:      "Number of failed reconciliations of the resources of a Capp, per resource manager.",
+	}, []string{labelManager})
+
+	// ResourceDrifts is the number of times fields managed by the operator were changed by someone else.
+	ResourceDrifts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "resource_drifts_total",
+		Help:      "Number of times fields of a Capp child resource managed by the operator were changed by someone else, per kind.",
+	}, []string{labelKind})
+
+	// OrphanedResources is the number of Capp child resources whose parent Capp no longer exists.
+	OrphanedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "orphaned_resources",
+		Help:      "Number of Capp child resources whose parent Capp no longer exists, per kind, as found by the last sweep.",
+	}, []string{labelKind})
+
+	// OrphanedResourcesDeleted is the number of orphaned Capp child resources deleted by the sweeper.
+	OrphanedResourcesDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orphaned_resources_deleted_total",
+		Help:      "Number of Capp child resources deleted after their parent Capp no longer existed, per kind.",
+	}, []string{labelKind})
+
+	// WebhookAdmissions is the number of admission requests handled by a webhook.
+	WebhookAdmissions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_admission_requests_total",
+		Help:      "Number of admission requests handled by a webhook, per result and reason.",
+	}, []string{labelWebhook, labelResult, labelReason})
+
+	cappsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "capps"),
+		"Number of Capps, per namespace and state.",
+		[]string{labelNamespace, labelState}, nil,
+	)
+
+	certificateExpiryDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "certificate_expiration_timestamp_seconds"),
+		"Expiration time of the certificate of a Capp hostname, in seconds since the epoch.",
+		[]string{labelNamespace, labelCapp, labelHostname}, nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(ResourceManagerDuration, ResourceManagerErrors, ResourceDrifts, OrphanedResources,
+		OrphanedResourcesDeleted, WebhookAdmissions)
+}
+
+// RecordAdmission records the result of an admission request handled by the given webhook.
+func RecordAdmission(webhook, result, reason string) {
+	WebhookAdmissions.WithLabelValues(webhook, result, reason).Inc()
+}
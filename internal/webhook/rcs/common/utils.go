@@ -5,17 +5,24 @@ import (
 	"fmt"
 
 	"net"
+	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
-	"k8s.io/apimachinery/pkg/types"
 
 	v1alpha2 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/network"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -69,10 +76,43 @@ func IsDomainNameTaken(domainName string) (bool, error) {
 	return true, nil
 }
 
+// ValidateAdditionalHostnames checks that the additional hostnames of the route are valid domain names
+// which are set together with the hostname and are unique.
+func ValidateAdditionalHostnames(routeSpec v1alpha2.RouteSpec, allowedPatterns []string) (errs *apis.FieldError) {
+	if len(routeSpec.AdditionalHostnames) == 0 {
+		return nil
+	}
+
+	if routeSpec.Hostname == "" {
+		errs = errs.Also(apis.ErrGeneric("invalid additional hostnames: hostname must be set", "routeSpec.additionalHostnames"))
+	}
+
+	hostnames := map[string]bool{routeSpec.Hostname: true}
+	for _, hostname := range routeSpec.AdditionalHostnames {
+		if hostname.Hostname == "" {
+			errs = errs.Also(apis.ErrGeneric("invalid additional hostname: hostname is required", "routeSpec.additionalHostnames.hostname"))
+			continue
+		}
+		if hostnames[hostname.Hostname] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid additional hostname %q: hostnames must be unique", hostname.Hostname), "routeSpec.additionalHostnames.hostname"))
+		}
+		hostnames[hostname.Hostname] = true
+
+		errs = errs.Also(ValidateDomainName(hostname.Hostname, allowedPatterns))
+	}
+
+	return errs
+}
+
 // ValidateLogSpec checks if the LogSpec is valid based on the Type field.
-func ValidateLogSpec(logSpec v1alpha2.LogSpec) *apis.FieldError {
+// The Elasticsearch index is not required when the CappConfig sets the given default index.
+func ValidateLogSpec(logSpec v1alpha2.LogSpec, defaultIndex string) *apis.FieldError {
 	requiredFields := map[string][]string{
 		"elastic": {"Host", "Index", "User", "PasswordSecret"},
+		"splunk":  {"Host", "Index", "PasswordSecret"},
+		"loki":    {"Host"},
+		"kafka":   {"Host", "Topic"},
+		"syslog":  {"Host"},
 	}
 	required, exists := requiredFields[logSpec.Type]
 	if !exists {
@@ -80,17 +120,76 @@ func ValidateLogSpec(logSpec v1alpha2.LogSpec) *apis.FieldError {
 		for validType := range requiredFields {
 			validTypes = append(validTypes, validType)
 		}
+		slices.Sort(validTypes)
 		return apis.ErrGeneric(
 			fmt.Sprintf("Invalid LogSpec Type: %q. Valid types are: %q", logSpec.Type, strings.Join(validTypes, ", ")),
 			"logSpec.Type")
 	}
+	if logSpec.Type == "elastic" && defaultIndex != "" {
+		required = slices.DeleteFunc(slices.Clone(required), func(field string) bool { return field == "Index" })
+	}
 	missingFields := findMissingFields(logSpec, required)
 	if len(missingFields) > 0 {
 		return apis.ErrGeneric(
 			fmt.Sprintf("%s log configuration is missing required fields: %q", logSpec.Type, strings.Join(missingFields, ", ")),
 			"logSpec")
 	}
-	return nil
+	if logSpec.Type == "loki" && ((logSpec.TLS.PeerVerify != nil && !*logSpec.TLS.PeerVerify) || logSpec.TLS.SSLVersion != "") {
+		return apis.ErrGeneric(
+			fmt.Sprintf("%s log configuration always verifies the peer and does not support setting the tls version", logSpec.Type),
+			"logSpec.tls")
+	}
+	if logSpec.User != "" && logSpec.PasswordSecret == "" {
+		return apis.ErrGeneric(
+			fmt.Sprintf("%s log configuration with a user is missing required fields: %q", logSpec.Type, "PasswordSecret"),
+			"logSpec")
+	}
+	if logSpec.Type == "elastic" && logSpec.Index != "" {
+		if _, err := utils.ParseLogIndex(logSpec.Index); err != nil {
+			return apis.ErrGeneric(fmt.Sprintf("invalid index %q: %s", logSpec.Index, err.Error()), "logSpec.index")
+		}
+	}
+	return validateLogFiltersAndParser(logSpec)
+}
+
+// validateLogFiltersAndParser checks that the filter and parser patterns of the LogSpec are valid regular expressions,
+// that the severity floor is known, and that every parser pattern has a named capture group.
+func validateLogFiltersAndParser(logSpec v1alpha2.LogSpec) (errs *apis.FieldError) {
+	logSeverities := []string{"info", "warning", "error", "critical"}
+
+	if logFilters := logSpec.Filters; logFilters != nil {
+		for _, pattern := range logFilters.Include {
+			if _, err := regexp.Compile(pattern); err != nil {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid include filter %q: %s", pattern, err.Error()), "logSpec.filters.include"))
+			}
+		}
+		for _, pattern := range logFilters.Exclude {
+			if _, err := regexp.Compile(pattern); err != nil {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid exclude filter %q: %s", pattern, err.Error()), "logSpec.filters.exclude"))
+			}
+		}
+		if logFilters.MinSeverity != "" && !slices.Contains(logSeverities, logFilters.MinSeverity) {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid minSeverity %q: must be one of %q", logFilters.MinSeverity, strings.Join(logSeverities, ", ")), "logSpec.filters.minSeverity"))
+		}
+	}
+
+	if logParser := logSpec.Parser; logParser != nil {
+		if len(logParser.Patterns) == 0 {
+			errs = errs.Also(apis.ErrGeneric("invalid parser: must define at least one pattern", "logSpec.parser.patterns"))
+		}
+		for _, pattern := range logParser.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid parser pattern %q: %s", pattern, err.Error()), "logSpec.parser.patterns"))
+				continue
+			}
+			if !slices.ContainsFunc(re.SubexpNames(), func(name string) bool { return name != "" }) {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid parser pattern %q: must have a named capture group", pattern), "logSpec.parser.patterns"))
+			}
+		}
+	}
+
+	return errs
 }
 
 // findMissingFields checks for missing fields in LogSpec.
@@ -101,6 +200,7 @@ func findMissingFields(logSpec v1alpha2.LogSpec, required []string) []string {
 		"Index":          logSpec.Index,
 		"User":           logSpec.User,
 		"PasswordSecret": logSpec.PasswordSecret,
+		"Topic":          logSpec.Topic,
 	}
 	for _, reqField := range required {
 		if value, ok := fieldValues[reqField]; !ok || value == "" {
@@ -110,13 +210,234 @@ func findMissingFields(logSpec v1alpha2.LogSpec, required []string) []string {
 	return missingFields
 }
 
-// GetCappConfig returns an instance of Capp Config.
-func GetCappConfig(ctx context.Context, k8sClient client.Client) (*v1alpha2.CappConfig, error) {
-	config := v1alpha2.CappConfig{}
-	key := types.NamespacedName{Name: utils.CappConfigName, Namespace: utils.CappNS}
-	if err := k8sClient.Get(ctx, key, &config); err != nil {
-		return nil, err
+// ValidateLogDestinations checks that the names of the log destinations are unique DNS labels,
+// that the SyslogNGOutputs of the destinations can be named after the Capp, and that every destination is valid.
+func ValidateLogDestinations(cappName string, destinations []v1alpha2.LogDestination, defaultIndex string) (errs *apis.FieldError) {
+	names := map[string]bool{}
+	for _, destination := range destinations {
+		if msgs := validation.IsDNS1123Label(destination.Name); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid log destination %q: %s", destination.Name, strings.Join(msgs, ", ")), "logDestinations.name"))
+		}
+		if names[destination.Name] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid log destination %q: log destination names must be unique", destination.Name), "logDestinations.name"))
+		}
+		names[destination.Name] = true
+
+		outputName := utils.GetLogDestinationResourceName(cappName, destination.Name)
+		if msgs := validation.IsDNS1123Subdomain(outputName); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid log destination %q: output name %q: %s", destination.Name, outputName, strings.Join(msgs, ", ")), "logDestinations.name"))
+		}
+
+		if err := ValidateLogSpec(destination.LogSpec, defaultIndex); err != nil {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid log destination %q: %s", destination.Name, err.Error()), "logDestinations"))
+		}
+	}
+
+	return errs
+}
+
+// IsLogDestinationNameTaken checks if the SyslogNGOutput or SyslogNGFlow of the given log destination of the Capp
+// already exists and does not belong to the Capp. The names of the resources of different Capps may collide,
+// e.g. destination "logs" of Capp "web" and the default destination of Capp "web-logs".
+func IsLogDestinationNameTaken(ctx context.Context, k8sClient client.Client, capp v1alpha2.Capp, destination v1alpha2.LogDestination) (bool, error) {
+	key := client.ObjectKey{Namespace: capp.Namespace, Name: utils.GetLogDestinationResourceName(capp.Name, destination.Name)}
+
+	for _, object := range []client.Object{&loggingv1beta1.SyslogNGOutput{}, &loggingv1beta1.SyslogNGFlow{}} {
+		if err := k8sClient.Get(ctx, key, object); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if object.GetLabels()[utils.CappResourceKey] != capp.Name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ValidateSources checks that every Keda source defines at least one trigger and that
+// sources using scaling modifiers only define named triggers.
+func ValidateSources(sources []v1alpha2.KedaSource) (errs *apis.FieldError) {
+	sourceNames := map[string]bool{}
+	triggerAuths := map[string]v1alpha2.TriggerAuth{}
+	for _, source := range sources {
+		if sourceNames[source.Name] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid source %q: source names must be unique", source.Name), "sources.name"))
+		}
+		sourceNames[source.Name] = true
+
+		for _, triggerAuth := range getSourceTriggerAuths(source) {
+			key := triggerAuth.Type + "/" + triggerAuth.Name
+			if previous, ok := triggerAuths[key]; ok && !equality.Semantic.DeepEqual(previous, triggerAuth) {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid source %q: triggerAuth %q is defined more than once with different specs", source.Name, triggerAuth.Name), "sources.triggerAuth.name"))
+			}
+			triggerAuths[key] = triggerAuth
+		}
+
+		if source.ScalarType == "" && len(source.Triggers) == 0 {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid source %q: must define either scalarType or triggers", source.Name), "sources"))
+			continue
+		}
+
+		triggerNames := map[string]bool{}
+		for _, trigger := range source.Triggers {
+			if trigger.Type == "" {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid source %q: every trigger must define a type", source.Name), "sources.triggers.type"))
+			}
+			if trigger.Name == "" {
+				continue
+			}
+			if triggerNames[trigger.Name] {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid source %q: trigger names must be unique, %q is repeated", source.Name, trigger.Name), "sources.triggers.name"))
+			}
+			triggerNames[trigger.Name] = true
+		}
+
+		if source.Advanced == nil || source.Advanced.ScalingModifiers == nil {
+			continue
+		}
+
+		scalingModifiers := source.Advanced.ScalingModifiers
+		if scalingModifiers.Formula == "" || scalingModifiers.Target == "" {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid source %q: scalingModifiers must define both formula and target", source.Name), "sources.advanced.scalingModifiers"))
+		}
+		if len(source.Triggers) == 0 || len(triggerNames) != len(source.Triggers) {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid source %q: scalingModifiers require every trigger to be named", source.Name), "sources.triggers.name"))
+		}
+	}
+
+	return errs
+}
+
+// getSourceTriggerAuths returns the trigger authentications of the triggers of a given source. A source which does
+// not define a list of triggers uses its own trigger authentication.
+func getSourceTriggerAuths(source v1alpha2.KedaSource) []v1alpha2.TriggerAuth {
+	var triggerAuths []v1alpha2.TriggerAuth
+	if len(source.Triggers) == 0 {
+		if source.TriggerAuth != nil {
+			triggerAuths = append(triggerAuths, *source.TriggerAuth)
+		}
+		return triggerAuths
+	}
+
+	for _, trigger := range source.Triggers {
+		if trigger.TriggerAuth != nil {
+			triggerAuths = append(triggerAuths, *trigger.TriggerAuth)
+		}
+	}
+	return triggerAuths
+}
+
+// ValidateTrafficTargets checks that every traffic target points either at the latest revision or at a pinned
+// revision, that tags are unique DNS labels, and that the percentages of the targets sum to 100.
+func ValidateTrafficTargets(trafficTargets []knativev1.TrafficTarget) (errs *apis.FieldError) {
+	if len(trafficTargets) == 0 {
+		return nil
 	}
 
-	return &config, nil
+	var totalPercent int64
+	tags := map[string]bool{}
+	for _, target := range trafficTargets {
+		latestRevision := target.LatestRevision != nil && *target.LatestRevision
+		if target.ConfigurationName != "" {
+			errs = errs.Also(apis.ErrGeneric("invalid traffic target: configurationName is not supported, use revisionName or latestRevision", "routeSpec.trafficTargets.configurationName"))
+		}
+		if latestRevision && target.RevisionName != "" {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid traffic target %q: latestRevision must not be set together with revisionName", target.RevisionName), "routeSpec.trafficTargets"))
+		}
+		if target.LatestRevision != nil && !*target.LatestRevision && target.RevisionName == "" {
+			errs = errs.Also(apis.ErrGeneric("invalid traffic target: revisionName is required when latestRevision is false", "routeSpec.trafficTargets.revisionName"))
+		}
+
+		if target.Tag != "" {
+			if msgs := validation.IsDNS1035Label(target.Tag); len(msgs) > 0 {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid tag %q: %s", target.Tag, strings.Join(msgs, ", ")), "routeSpec.trafficTargets.tag"))
+			}
+			if tags[target.Tag] {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid tag %q: tags must be unique", target.Tag), "routeSpec.trafficTargets.tag"))
+			}
+			tags[target.Tag] = true
+		}
+
+		if target.Percent != nil {
+			if *target.Percent < 0 || *target.Percent > 100 {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid percent %d: must be between 0 and 100", *target.Percent), "routeSpec.trafficTargets.percent"))
+			}
+			totalPercent += *target.Percent
+		}
+	}
+
+	if totalPercent != 100 {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid traffic targets: percentages must sum to 100, got %d", totalPercent), "routeSpec.trafficTargets.percent"))
+	}
+
+	return errs
+}
+
+// ValidateRollout checks the rollout strategy is valid and does not conflict with explicit traffic targets.
+func ValidateRollout(rollout *v1alpha2.RolloutSpec, routeSpec v1alpha2.RouteSpec) (errs *apis.FieldError) {
+	if rollout == nil {
+		return nil
+	}
+
+	if len(utils.GetTrafficTargets(routeSpec)) > 0 {
+		errs = errs.Also(apis.ErrGeneric("invalid rollout: rollout must not be set together with routeSpec traffic targets", "rollout"))
+	}
+
+	if len(rollout.Steps) == 0 {
+		errs = errs.Also(apis.ErrGeneric("invalid rollout: at least one step is required", "rollout.steps"))
+	}
+
+	var previousPercent int64
+	for i, step := range rollout.Steps {
+		if step.Percent < 0 || step.Percent > 100 {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid step %d: percent %d must be between 0 and 100", i, step.Percent), "rollout.steps.percent"))
+		}
+		if step.Percent < previousPercent {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid step %d: percent %d must not be lower than the percent of the previous step", i, step.Percent), "rollout.steps.percent"))
+		}
+		previousPercent = step.Percent
+	}
+
+	if rollout.Analysis == nil {
+		return errs
+	}
+
+	address, err := url.Parse(rollout.Analysis.Address)
+	if err != nil || (address.Scheme != "http" && address.Scheme != "https") || address.Host == "" {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid analysis address %q: must be an http or https URL", rollout.Analysis.Address), "rollout.analysis.address"))
+	}
+
+	if len(rollout.Analysis.Metrics) == 0 {
+		errs = errs.Also(apis.ErrGeneric("invalid analysis: at least one metric is required", "rollout.analysis.metrics"))
+	}
+
+	for _, metric := range rollout.Analysis.Metrics {
+		if metric.Name == "" {
+			errs = errs.Also(apis.ErrGeneric("invalid metric: name is required", "rollout.analysis.metrics.name"))
+		}
+		if metric.Query == "" {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid metric %q: query is required", metric.Name), "rollout.analysis.metrics.query"))
+		} else if _, err := template.New(metric.Name).Parse(metric.Query); err != nil {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid metric %q: query is not a valid template: %s", metric.Name, err.Error()), "rollout.analysis.metrics.query"))
+		}
+		if metric.Min == "" && metric.Max == "" {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid metric %q: at least one of min or max is required", metric.Name), "rollout.analysis.metrics"))
+		}
+		if _, err := strconv.ParseFloat(metric.Min, 64); metric.Min != "" && err != nil {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid metric %q: min %q is not a number", metric.Name, metric.Min), "rollout.analysis.metrics.min"))
+		}
+		if _, err := strconv.ParseFloat(metric.Max, 64); metric.Max != "" && err != nil {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("invalid metric %q: max %q is not a number", metric.Name, metric.Max), "rollout.analysis.metrics.max"))
+		}
+	}
+
+	return errs
+}
+
+// GetCappConfig returns the instance of Capp Config which applies to the Capps in the given namespace.
+func GetCappConfig(ctx context.Context, k8sClient client.Client, namespace string) (*v1alpha2.CappConfig, error) {
+	return utils.ResolveCappConfig(ctx, k8sClient, namespace)
 }
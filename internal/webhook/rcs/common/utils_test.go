@@ -1,10 +1,21 @@
 package common
 
 import (
+	"context"
 	"strings"
 	"testing"
 
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	loggingv1beta1 "github.com/kube-logging/logging-operator/pkg/sdk/logging/api/v1beta1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestValidateDomainName(t *testing.T) {
@@ -77,3 +88,602 @@ func TestValidateDomainName(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAdditionalHostnames(t *testing.T) {
+	tests := []struct {
+		name          string
+		routeSpec     cappv1alpha1.RouteSpec
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:        "No additional hostnames",
+			routeSpec:   cappv1alpha1.RouteSpec{Hostname: "myapp.example.com"},
+			expectError: false,
+		},
+		{
+			name: "Valid additional hostnames",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname:            "myapp.example.com",
+				AdditionalHostnames: []cappv1alpha1.HostnameSpec{{Hostname: "myapp.legacy.com", TlsEnabled: true}},
+			},
+			expectError: false,
+		},
+		{
+			name: "Additional hostnames without hostname",
+			routeSpec: cappv1alpha1.RouteSpec{
+				AdditionalHostnames: []cappv1alpha1.HostnameSpec{{Hostname: "myapp.legacy.com"}},
+			},
+			expectError:   true,
+			errorContains: "hostname must be set",
+		},
+		{
+			name: "Duplicate hostname",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname:            "myapp.example.com",
+				AdditionalHostnames: []cappv1alpha1.HostnameSpec{{Hostname: "myapp.example.com"}},
+			},
+			expectError:   true,
+			errorContains: "hostnames must be unique",
+		},
+		{
+			name: "Additional hostname not matching pattern",
+			routeSpec: cappv1alpha1.RouteSpec{
+				Hostname:            "myapp.example.com",
+				AdditionalHostnames: []cappv1alpha1.HostnameSpec{{Hostname: "myapp.other.org"}},
+			},
+			expectError:   true,
+			errorContains: "must match one of the allowed patterns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateAdditionalHostnames(tt.routeSpec, []string{`.*\.com`})
+			if tt.expectError {
+				assert.NotNil(t, errs)
+				if tt.errorContains != "" {
+					assert.True(t, strings.Contains(errs.Error(), tt.errorContains), "Expected error to contain %q, got %q", tt.errorContains, errs.Error())
+				}
+			} else {
+				assert.Nil(t, errs)
+			}
+		})
+	}
+}
+
+func TestValidateSources(t *testing.T) {
+	scalingModifiers := &cappv1alpha1.KedaAdvanced{
+		ScalingModifiers: &cappv1alpha1.ScalingModifiers{Formula: "lag + queries", Target: "10"},
+	}
+
+	tests := []struct {
+		name          string
+		sources       []cappv1alpha1.KedaSource
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:        "Single scalar source",
+			sources:     []cappv1alpha1.KedaSource{{Name: "kafka", ScalarType: "kafka"}},
+			expectError: false,
+		},
+		{
+			name: "Source with multiple triggers",
+			sources: []cappv1alpha1.KedaSource{{Name: "queue", Triggers: []cappv1alpha1.KedaTrigger{
+				{Type: "kafka"},
+				{Type: "prometheus"},
+			}}},
+			expectError: false,
+		},
+		{
+			name:          "Source without triggers",
+			sources:       []cappv1alpha1.KedaSource{{Name: "empty"}},
+			expectError:   true,
+			errorContains: "must define either scalarType or triggers",
+		},
+		{
+			name:          "Duplicate source names",
+			sources:       []cappv1alpha1.KedaSource{{Name: "kafka", ScalarType: "kafka"}, {Name: "kafka", ScalarType: "kafka"}},
+			expectError:   true,
+			errorContains: "source names must be unique",
+		},
+		{
+			name:          "Trigger without type",
+			sources:       []cappv1alpha1.KedaSource{{Name: "queue", Triggers: []cappv1alpha1.KedaTrigger{{Name: "lag"}}}},
+			expectError:   true,
+			errorContains: "every trigger must define a type",
+		},
+		{
+			name: "Shared triggerAuth with the same spec",
+			sources: []cappv1alpha1.KedaSource{
+				{Name: "kafka", ScalarType: "kafka", TriggerAuth: &cappv1alpha1.TriggerAuth{Type: "triggerAuthentication", Name: "kafka-auth",
+					SecretTargets: []cappv1alpha1.AuthSecretTarget{{Parameter: "sasl", SecretRef: corev1.SecretKeySelector{Key: "sasl"}}}}},
+				{Name: "queue", Triggers: []cappv1alpha1.KedaTrigger{{Type: "kafka", TriggerAuth: &cappv1alpha1.TriggerAuth{Type: "triggerAuthentication", Name: "kafka-auth",
+					SecretTargets: []cappv1alpha1.AuthSecretTarget{{Parameter: "sasl", SecretRef: corev1.SecretKeySelector{Key: "sasl"}}}}}}},
+			},
+			expectError: false,
+		},
+		{
+			name: "Shared triggerAuth with different specs",
+			sources: []cappv1alpha1.KedaSource{
+				{Name: "kafka", ScalarType: "kafka", TriggerAuth: &cappv1alpha1.TriggerAuth{Type: "triggerAuthentication", Name: "kafka-auth",
+					SecretTargets: []cappv1alpha1.AuthSecretTarget{{Parameter: "sasl", SecretRef: corev1.SecretKeySelector{Key: "sasl"}}}}},
+				{Name: "queue", Triggers: []cappv1alpha1.KedaTrigger{{Type: "kafka", TriggerAuth: &cappv1alpha1.TriggerAuth{Type: "triggerAuthentication", Name: "kafka-auth",
+					SecretTargets: []cappv1alpha1.AuthSecretTarget{{Parameter: "password", SecretRef: corev1.SecretKeySelector{Key: "password"}}}}}}},
+			},
+			expectError:   true,
+			errorContains: `triggerAuth "kafka-auth" is defined more than once with different specs`,
+		},
+		{
+			name: "Scaling modifiers with named triggers",
+			sources: []cappv1alpha1.KedaSource{{Name: "queue", Advanced: scalingModifiers, Triggers: []cappv1alpha1.KedaTrigger{
+				{Name: "lag", Type: "kafka"},
+				{Name: "queries", Type: "prometheus"},
+			}}},
+			expectError: false,
+		},
+		{
+			name: "Scaling modifiers with unnamed trigger",
+			sources: []cappv1alpha1.KedaSource{{Name: "queue", Advanced: scalingModifiers, Triggers: []cappv1alpha1.KedaTrigger{
+				{Name: "lag", Type: "kafka"},
+				{Type: "prometheus"},
+			}}},
+			expectError:   true,
+			errorContains: "scalingModifiers require every trigger to be named",
+		},
+		{
+			name: "Scaling modifiers without formula",
+			sources: []cappv1alpha1.KedaSource{{
+				Name:     "queue",
+				Advanced: &cappv1alpha1.KedaAdvanced{ScalingModifiers: &cappv1alpha1.ScalingModifiers{Target: "10"}},
+				Triggers: []cappv1alpha1.KedaTrigger{{Name: "lag", Type: "kafka"}},
+			}},
+			expectError:   true,
+			errorContains: "must define both formula and target",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateSources(tt.sources)
+			if tt.expectError {
+				assert.NotNil(t, errs)
+				if tt.errorContains != "" {
+					assert.True(t, strings.Contains(errs.Error(), tt.errorContains), "Expected error to contain %q, got %q", tt.errorContains, errs.Error())
+				}
+			} else {
+				assert.Nil(t, errs)
+			}
+		})
+	}
+}
+
+func TestValidateTrafficTargets(t *testing.T) {
+	tests := []struct {
+		name           string
+		trafficTargets []knativev1.TrafficTarget
+		expectError    bool
+		errorContains  string
+	}{
+		{
+			name:        "No traffic targets",
+			expectError: false,
+		},
+		{
+			name: "Split between latest and pinned revisions",
+			trafficTargets: []knativev1.TrafficTarget{
+				{LatestRevision: ptr.To(true), Percent: ptr.To(int64(80))},
+				{RevisionName: "app-00001", Percent: ptr.To(int64(20)), Tag: "stable"},
+			},
+			expectError: false,
+		},
+		{
+			name: "Tagged revision without traffic",
+			trafficTargets: []knativev1.TrafficTarget{
+				{LatestRevision: ptr.To(true), Percent: ptr.To(int64(100))},
+				{RevisionName: "app-00002", Tag: "preview"},
+			},
+			expectError: false,
+		},
+		{
+			name: "Percentages do not sum to 100",
+			trafficTargets: []knativev1.TrafficTarget{
+				{LatestRevision: ptr.To(true), Percent: ptr.To(int64(80))},
+				{RevisionName: "app-00001", Percent: ptr.To(int64(10))},
+			},
+			expectError:   true,
+			errorContains: "percentages must sum to 100",
+		},
+		{
+			name: "Duplicate tags",
+			trafficTargets: []knativev1.TrafficTarget{
+				{LatestRevision: ptr.To(true), Percent: ptr.To(int64(50)), Tag: "canary"},
+				{RevisionName: "app-00001", Percent: ptr.To(int64(50)), Tag: "canary"},
+			},
+			expectError:   true,
+			errorContains: "tags must be unique",
+		},
+		{
+			name: "Invalid tag",
+			trafficTargets: []knativev1.TrafficTarget{
+				{LatestRevision: ptr.To(true), Percent: ptr.To(int64(100)), Tag: "Not_Valid"},
+			},
+			expectError:   true,
+			errorContains: "invalid tag",
+		},
+		{
+			name: "Latest revision together with revision name",
+			trafficTargets: []knativev1.TrafficTarget{
+				{LatestRevision: ptr.To(true), RevisionName: "app-00001", Percent: ptr.To(int64(100))},
+			},
+			expectError:   true,
+			errorContains: "latestRevision must not be set together with revisionName",
+		},
+		{
+			name: "Configuration name is not supported",
+			trafficTargets: []knativev1.TrafficTarget{
+				{ConfigurationName: "app", Percent: ptr.To(int64(100))},
+			},
+			expectError:   true,
+			errorContains: "configurationName is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateTrafficTargets(tt.trafficTargets)
+			if tt.expectError {
+				assert.NotNil(t, errs)
+				if tt.errorContains != "" {
+					assert.True(t, strings.Contains(errs.Error(), tt.errorContains), "Expected error to contain %q, got %q", tt.errorContains, errs.Error())
+				}
+			} else {
+				assert.Nil(t, errs)
+			}
+		})
+	}
+}
+
+func TestValidateRollout(t *testing.T) {
+	analysis := &cappv1alpha1.RolloutAnalysis{
+		Address: "http://prometheus.monitoring:9090",
+		Metrics: []cappv1alpha1.RolloutMetric{
+			{Name: "success-rate", Query: `sum(rate(requests{revision="{{ .Revision }}"}[1m]))`, Min: "0.99"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		rollout       *cappv1alpha1.RolloutSpec
+		routeSpec     cappv1alpha1.RouteSpec
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:        "No rollout",
+			expectError: false,
+		},
+		{
+			name: "Valid rollout",
+			rollout: &cappv1alpha1.RolloutSpec{
+				Steps:    []cappv1alpha1.RolloutStep{{Percent: 10}, {Percent: 30}, {Percent: 100}},
+				Analysis: analysis,
+			},
+			expectError: false,
+		},
+		{
+			name: "Rollout together with traffic targets",
+			rollout: &cappv1alpha1.RolloutSpec{
+				Steps: []cappv1alpha1.RolloutStep{{Percent: 100}},
+			},
+			routeSpec: cappv1alpha1.RouteSpec{
+				TrafficTargets: []knativev1.TrafficTarget{{LatestRevision: ptr.To(true), Percent: ptr.To(int64(100))}},
+			},
+			expectError:   true,
+			errorContains: "must not be set together with routeSpec traffic targets",
+		},
+		{
+			name: "Decreasing steps",
+			rollout: &cappv1alpha1.RolloutSpec{
+				Steps: []cappv1alpha1.RolloutStep{{Percent: 30}, {Percent: 10}},
+			},
+			expectError:   true,
+			errorContains: "must not be lower than the percent of the previous step",
+		},
+		{
+			name: "Invalid analysis address",
+			rollout: &cappv1alpha1.RolloutSpec{
+				Steps: []cappv1alpha1.RolloutStep{{Percent: 100}},
+				Analysis: &cappv1alpha1.RolloutAnalysis{
+					Address: "prometheus:9090",
+					Metrics: analysis.Metrics,
+				},
+			},
+			expectError:   true,
+			errorContains: "must be an http or https URL",
+		},
+		{
+			name: "Metric without thresholds",
+			rollout: &cappv1alpha1.RolloutSpec{
+				Steps: []cappv1alpha1.RolloutStep{{Percent: 100}},
+				Analysis: &cappv1alpha1.RolloutAnalysis{
+					Address: analysis.Address,
+					Metrics: []cappv1alpha1.RolloutMetric{{Name: "latency", Query: "up"}},
+				},
+			},
+			expectError:   true,
+			errorContains: "at least one of min or max is required",
+		},
+		{
+			name: "Metric with an invalid threshold",
+			rollout: &cappv1alpha1.RolloutSpec{
+				Steps: []cappv1alpha1.RolloutStep{{Percent: 100}},
+				Analysis: &cappv1alpha1.RolloutAnalysis{
+					Address: analysis.Address,
+					Metrics: []cappv1alpha1.RolloutMetric{{Name: "latency", Query: "up", Max: "fast"}},
+				},
+			},
+			expectError:   true,
+			errorContains: "is not a number",
+		},
+		{
+			name: "Metric with an invalid query template",
+			rollout: &cappv1alpha1.RolloutSpec{
+				Steps: []cappv1alpha1.RolloutStep{{Percent: 100}},
+				Analysis: &cappv1alpha1.RolloutAnalysis{
+					Address: analysis.Address,
+					Metrics: []cappv1alpha1.RolloutMetric{{Name: "latency", Query: "{{ .Revision", Max: "1"}},
+				},
+			},
+			expectError:   true,
+			errorContains: "is not a valid template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateRollout(tt.rollout, tt.routeSpec)
+			if tt.expectError {
+				assert.NotNil(t, errs)
+				if tt.errorContains != "" {
+					assert.True(t, strings.Contains(errs.Error(), tt.errorContains), "Expected error to contain %q, got %q", tt.errorContains, errs.Error())
+				}
+			} else {
+				assert.Nil(t, errs)
+			}
+		})
+	}
+}
+
+func TestValidateLogSpec(t *testing.T) {
+	tests := []struct {
+		name          string
+		logSpec       cappv1alpha1.LogSpec
+		defaultIndex  string
+		errorContains string
+	}{
+		{
+			name:    "Valid elastic log spec",
+			logSpec: cappv1alpha1.LogSpec{Type: "elastic", Host: "https://elastic:9200", Index: "main", User: "elastic", PasswordSecret: "es-secret"},
+		},
+		{
+			name:    "Valid splunk log spec",
+			logSpec: cappv1alpha1.LogSpec{Type: "splunk", Host: "https://splunk:8088", Index: "main", PasswordSecret: "hec-token"},
+		},
+		{
+			name:    "Valid loki log spec",
+			logSpec: cappv1alpha1.LogSpec{Type: "loki", Host: "loki.monitoring:9095"},
+		},
+		{
+			name:    "Valid kafka log spec",
+			logSpec: cappv1alpha1.LogSpec{Type: "kafka", Host: "http://kafka-rest:8082", Topic: "logs"},
+		},
+		{
+			name:    "Valid syslog log spec",
+			logSpec: cappv1alpha1.LogSpec{Type: "syslog", Host: "syslog.example.com", Port: 6514, Transport: "tls"},
+		},
+		{
+			name:    "Loki log spec with TLS options",
+			logSpec: cappv1alpha1.LogSpec{Type: "loki", Host: "loki.monitoring:9095", TLS: cappv1alpha1.LogTLS{CASecret: "loki-ca", ClientCertSecret: "loki-client"}},
+		},
+		{
+			name:          "Loki log spec without peer verification",
+			logSpec:       cappv1alpha1.LogSpec{Type: "loki", Host: "loki.monitoring:9095", TLS: cappv1alpha1.LogTLS{PeerVerify: ptr.To(false)}},
+			errorContains: "loki log configuration always verifies the peer",
+		},
+		{
+			name:          "Invalid log type",
+			logSpec:       cappv1alpha1.LogSpec{Type: "unsupported", Host: "host"},
+			errorContains: `Valid types are: "elastic, kafka, loki, splunk, syslog"`,
+		},
+		{
+			name:          "Splunk log spec without a token",
+			logSpec:       cappv1alpha1.LogSpec{Type: "splunk", Host: "https://splunk:8088", Index: "main"},
+			errorContains: `splunk log configuration is missing required fields: "PasswordSecret"`,
+		},
+		{
+			name:          "Kafka log spec without a topic",
+			logSpec:       cappv1alpha1.LogSpec{Type: "kafka", Host: "http://kafka-rest:8082"},
+			errorContains: `kafka log configuration is missing required fields: "Topic"`,
+		},
+		{
+			name:          "Kafka log spec with a user and without a password",
+			logSpec:       cappv1alpha1.LogSpec{Type: "kafka", Host: "http://kafka-rest:8082", Topic: "logs", User: "producer"},
+			errorContains: `kafka log configuration with a user is missing required fields: "PasswordSecret"`,
+		},
+		{
+			name: "Valid filters and parser",
+			logSpec: cappv1alpha1.LogSpec{
+				Type: "loki", Host: "loki.monitoring:9095",
+				Filters: &cappv1alpha1.LogFilters{Include: []string{"payment"}, Exclude: []string{"healthz|readyz"}, MinSeverity: "warning"},
+				Parser:  &cappv1alpha1.LogParser{Patterns: []string{`"level":"(?<level>[^"]+)"`}},
+			},
+		},
+		{
+			name: "Invalid include filter",
+			logSpec: cappv1alpha1.LogSpec{Type: "loki", Host: "loki.monitoring:9095",
+				Filters: &cappv1alpha1.LogFilters{Include: []string{"payment("}}},
+			errorContains: `invalid include filter "payment("`,
+		},
+		{
+			name: "Invalid exclude filter",
+			logSpec: cappv1alpha1.LogSpec{Type: "loki", Host: "loki.monitoring:9095",
+				Filters: &cappv1alpha1.LogFilters{Exclude: []string{"[healthz"}}},
+			errorContains: `invalid exclude filter "[healthz"`,
+		},
+		{
+			name: "Invalid minimum severity",
+			logSpec: cappv1alpha1.LogSpec{Type: "loki", Host: "loki.monitoring:9095",
+				Filters: &cappv1alpha1.LogFilters{MinSeverity: "debug"}},
+			errorContains: `invalid minSeverity "debug"`,
+		},
+		{
+			name: "Parser without patterns",
+			logSpec: cappv1alpha1.LogSpec{Type: "loki", Host: "loki.monitoring:9095",
+				Parser: &cappv1alpha1.LogParser{}},
+			errorContains: "invalid parser: must define at least one pattern",
+		},
+		{
+			name: "Parser pattern without a named group",
+			logSpec: cappv1alpha1.LogSpec{Type: "loki", Host: "loki.monitoring:9095",
+				Parser: &cappv1alpha1.LogParser{Patterns: []string{`"level":"([^"]+)"`}}},
+			errorContains: "must have a named capture group",
+		},
+		{
+			name:    "Elastic log spec with a templated index",
+			logSpec: cappv1alpha1.LogSpec{Type: "elastic", Host: "https://elastic:9200", Index: "{{ .NamespaceLabels.team | lower }}-{{ .Date }}", User: "elastic", PasswordSecret: "es-secret"},
+		},
+		{
+			name:         "Elastic log spec without an index and with a default index",
+			logSpec:      cappv1alpha1.LogSpec{Type: "elastic", Host: "https://elastic:9200", User: "elastic", PasswordSecret: "es-secret"},
+			defaultIndex: "{{ .Namespace }}-{{ .Date }}",
+		},
+		{
+			name:          "Elastic log spec without an index and without a default index",
+			logSpec:       cappv1alpha1.LogSpec{Type: "elastic", Host: "https://elastic:9200", User: "elastic", PasswordSecret: "es-secret"},
+			errorContains: `elastic log configuration is missing required fields: "Index"`,
+		},
+		{
+			name:          "Elastic log spec with an invalid index template",
+			logSpec:       cappv1alpha1.LogSpec{Type: "elastic", Host: "https://elastic:9200", Index: "{{ .Namespace", User: "elastic", PasswordSecret: "es-secret"},
+			errorContains: `invalid index "{{ .Namespace"`,
+		},
+		{
+			name:          "Splunk log spec without an index and with a default index",
+			logSpec:       cappv1alpha1.LogSpec{Type: "splunk", Host: "https://splunk:8088", PasswordSecret: "hec-token"},
+			defaultIndex:  "{{ .Namespace }}",
+			errorContains: `splunk log configuration is missing required fields: "Index"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLogSpec(tt.logSpec, tt.defaultIndex)
+			if tt.errorContains == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestValidateLogDestinations(t *testing.T) {
+	splunk := cappv1alpha1.LogSpec{Type: "splunk", Host: "https://splunk:8088", Index: "main", PasswordSecret: "hec-token"}
+
+	tests := []struct {
+		name          string
+		destinations  []cappv1alpha1.LogDestination
+		errorContains string
+	}{
+		{
+			name:         "No destinations",
+			destinations: nil,
+		},
+		{
+			name: "Valid destinations",
+			destinations: []cappv1alpha1.LogDestination{
+				{Name: "security", LogSpec: splunk},
+				{Name: "audit", LogSpec: cappv1alpha1.LogSpec{Type: "syslog", Host: "syslog.example.com"}},
+			},
+		},
+		{
+			name: "Duplicate destination names",
+			destinations: []cappv1alpha1.LogDestination{
+				{Name: "security", LogSpec: splunk},
+				{Name: "security", LogSpec: splunk},
+			},
+			errorContains: "log destination names must be unique",
+		},
+		{
+			name:          "Destination name which is not a DNS label",
+			destinations:  []cappv1alpha1.LogDestination{{Name: "Security_Logs", LogSpec: splunk}},
+			errorContains: `invalid log destination "Security_Logs"`,
+		},
+		{
+			name:          "Invalid destination",
+			destinations:  []cappv1alpha1.LogDestination{{Name: "security", LogSpec: cappv1alpha1.LogSpec{Type: "splunk", Host: "https://splunk:8088"}}},
+			errorContains: `splunk log configuration is missing required fields: "Index, PasswordSecret"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLogDestinations("test-capp", tt.destinations, "")
+			if tt.errorContains == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestIsLogDestinationNameTaken(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, loggingv1beta1.AddToScheme(scheme))
+
+	labels := func(cappName string) map[string]string {
+		return map[string]string{utils.CappResourceKey: cappName}
+	}
+
+	objects := []client.Object{
+		&loggingv1beta1.SyslogNGOutput{ObjectMeta: metav1.ObjectMeta{Name: "web-logs", Namespace: "test-ns", Labels: labels("web-logs")}},
+		&loggingv1beta1.SyslogNGFlow{ObjectMeta: metav1.ObjectMeta{Name: "web-audit", Namespace: "test-ns", Labels: labels("web-audit")}},
+		&loggingv1beta1.SyslogNGOutput{ObjectMeta: metav1.ObjectMeta{Name: "web-errors", Namespace: "test-ns", Labels: labels("web")}},
+		&loggingv1beta1.SyslogNGOutput{ObjectMeta: metav1.ObjectMeta{Name: "web-manual", Namespace: "test-ns"}},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	capp := cappv1alpha1.Capp{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test-ns"}}
+
+	tests := []struct {
+		name        string
+		destination string
+		namespace   string
+		expectTaken bool
+	}{
+		{name: "Output of another Capp", destination: "logs", expectTaken: true},
+		{name: "Flow of another Capp", destination: "audit", expectTaken: true},
+		{name: "Output of the Capp", destination: "errors", expectTaken: false},
+		{name: "Output not created by a Capp", destination: "manual", expectTaken: true},
+		{name: "No output", destination: "security", expectTaken: false},
+		{name: "Output of another Capp in another namespace", destination: "logs", namespace: "other-ns", expectTaken: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capp := *capp.DeepCopy()
+			if tt.namespace != "" {
+				capp.Namespace = tt.namespace
+			}
+
+			taken, err := IsLogDestinationNameTaken(context.Background(), k8sClient, capp, cappv1alpha1.LogDestination{Name: tt.destination})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectTaken, taken)
+		})
+	}
+}
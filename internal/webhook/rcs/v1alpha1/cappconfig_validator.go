@@ -0,0 +1,86 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/cappconfig/validation"
+	"github.com/dana-team/container-app-operator/internal/metrics"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	cappConfigValidatorWebhookName = "cappconfig-validator"
+
+	reasonInvalidCappConfig   = "InvalidCappConfig"
+	reasonReferenceCheckError = "ReferenceCheckError"
+)
+
+type CappConfigValidator struct {
+	Client  client.Client
+	Decoder admission.Decoder
+	Log     logr.Logger
+}
+
+// The webhook ignores failures, so that the CappConfig can be installed together with the operator before the
+// webhook is served. The CappConfig controller reports an invalid CappConfig in its status in any case.
+// +kubebuilder:webhook:path=/validate-cappconfig,mutating=false,sideEffects=None,failurePolicy=ignore,groups="rcs.dana.io",resources=cappconfigs,verbs=create;update,versions=v1alpha1,name=cappconfig.validate.rcs.dana.io,admissionReviewVersions=v1;v1beta1
+
+func (c *CappConfigValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	logger := log.FromContext(ctx).WithValues("webhook", "cappConfig Webhook", "Name", req.Name)
+	logger.Info("Webhook request received")
+
+	cappConfig := cappv1alpha1.CappConfig{}
+	if err := c.Decoder.DecodeRaw(req.Object, &cappConfig); err != nil {
+		logger.Error(err, "could not decode cappConfig object")
+		metrics.RecordAdmission(cappConfigValidatorWebhookName, metrics.ResultErrored, reasonDecodeError)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if cappConfig.Namespace == "" {
+		cappConfig.Namespace = req.Namespace
+	}
+
+	return c.handle(ctx, cappConfig)
+}
+
+// handle denies a CappConfig with invalid values. A CappConfig which references a ClusterIssuer or a
+// ClusterProviderConfig that does not exist is allowed with a warning, since they may be created later.
+func (c *CappConfigValidator) handle(ctx context.Context, cappConfig cappv1alpha1.CappConfig) admission.Response {
+	if errs := validation.ValidateSpec(cappConfig); errs != nil {
+		metrics.RecordAdmission(cappConfigValidatorWebhookName, metrics.ResultDenied, reasonInvalidCappConfig)
+		return admission.Denied(errs.Error())
+	}
+
+	var warnings []string
+	dnsConfig := cappConfig.Spec.DNSConfig
+	if dnsConfig.Issuer != "" {
+		available, err := validation.IsClusterIssuerAvailable(ctx, c.Client, dnsConfig.Issuer)
+		if err != nil {
+			metrics.RecordAdmission(cappConfigValidatorWebhookName, metrics.ResultErrored, reasonReferenceCheckError)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if !available {
+			warnings = append(warnings, fmt.Sprintf("ClusterIssuer %q does not exist", dnsConfig.Issuer))
+		}
+	}
+
+	if dnsConfig.Provider != "" {
+		available, err := validation.IsProviderConfigAvailable(ctx, c.Client, dnsConfig.Provider)
+		if err != nil {
+			metrics.RecordAdmission(cappConfigValidatorWebhookName, metrics.ResultErrored, reasonReferenceCheckError)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if !available {
+			warnings = append(warnings, fmt.Sprintf("ClusterProviderConfig %q does not exist", dnsConfig.Provider))
+		}
+	}
+
+	metrics.RecordAdmission(cappConfigValidatorWebhookName, metrics.ResultAllowed, "")
+	return admission.Allowed("").WithWarnings(warnings...)
+}
@@ -0,0 +1,110 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	dnsv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestCappConfigValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(cappv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(cmapi.AddToScheme(scheme))
+	utilruntime.Must(dnsv1beta1.SchemeBuilder.AddToScheme(scheme))
+
+	decoder := admission.NewDecoder(scheme)
+
+	issuer := &cmapi.ClusterIssuer{ObjectMeta: metav1.ObjectMeta{Name: "cert-issuer"}}
+	providerConfig := &dnsv1beta1.ClusterProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "dns-default"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(issuer, providerConfig).Build()
+
+	dnsConfig := cappv1alpha1.DNSConfig{Zone: "cluster.dev.", CNAME: "ingress.cluster.dev.", Provider: "dns-default", Issuer: "cert-issuer"}
+
+	tests := []struct {
+		name          string
+		cappConfig    *cappv1alpha1.CappConfig
+		expectAllow   bool
+		expectMsg     string
+		expectWarning string
+	}{
+		{
+			name: "Allow valid cluster CappConfig",
+			cappConfig: &cappv1alpha1.CappConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: utils.CappConfigName, Namespace: utils.CappNS},
+				Spec:       cappv1alpha1.CappConfigSpec{DNSConfig: dnsConfig},
+			},
+			expectAllow: true,
+		},
+		{
+			name: "Deny cluster CappConfig with invalid hostname pattern",
+			cappConfig: &cappv1alpha1.CappConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: utils.CappConfigName, Namespace: utils.CappNS},
+				Spec:       cappv1alpha1.CappConfigSpec{DNSConfig: dnsConfig, AllowedHostnamePatterns: []string{"("}},
+			},
+			expectAllow: false,
+			expectMsg:   "allowedHostnamePatterns[0]",
+		},
+		{
+			name: "Allow override with missing ClusterIssuer with a warning",
+			cappConfig: &cappv1alpha1.CappConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: utils.CappNS},
+				Spec: cappv1alpha1.CappConfigSpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}},
+					DNSConfig:         cappv1alpha1.DNSConfig{Issuer: "missing-issuer"},
+				},
+			},
+			expectAllow:   true,
+			expectWarning: "ClusterIssuer \"missing-issuer\" does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := &CappConfigValidator{
+				Client:  fakeClient,
+				Decoder: decoder,
+			}
+
+			raw, err := json.Marshal(tc.cappConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object: runtime.RawExtension{
+						Raw: raw,
+					},
+					Name:      tc.cappConfig.Name,
+					Namespace: tc.cappConfig.Namespace,
+				},
+			}
+
+			resp := validator.Handle(context.Background(), req)
+			assert.Equal(t, tc.expectAllow, resp.Allowed, "Expected allowed: %v, got: %v. Result: %v", tc.expectAllow, resp.Allowed, resp.Result)
+			if !tc.expectAllow && tc.expectMsg != "" {
+				assert.Contains(t, resp.Result.Message, tc.expectMsg)
+			}
+			if tc.expectWarning != "" {
+				assert.Contains(t, resp.Warnings, tc.expectWarning)
+			} else {
+				assert.Empty(t, resp.Warnings)
+			}
+		})
+	}
+}
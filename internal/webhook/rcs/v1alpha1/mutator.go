@@ -37,7 +37,7 @@ func (c *CappMutator) Handle(ctx context.Context, req admission.Request) admissi
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	cappConfig, err := common.GetCappConfig(ctx, c.Client)
+	cappConfig, err := common.GetCappConfig(ctx, c.Client, req.Namespace)
 	if err != nil {
 		logger.Error(err, "failed to get RCS Config")
 		return admission.Errored(http.StatusInternalServerError, err)
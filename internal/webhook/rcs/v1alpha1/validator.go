@@ -5,8 +5,12 @@ import (
 	"fmt"
 
 	"net/http"
+	"slices"
 
 	cappv1alpha1 "github.com/dana-team/container-app-operator/api/v1alpha1"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/rollout"
+	"github.com/dana-team/container-app-operator/internal/kinds/capp/utils"
+	"github.com/dana-team/container-app-operator/internal/metrics"
 	"github.com/dana-team/container-app-operator/internal/webhook/rcs/common"
 
 	admissionv1 "k8s.io/api/admission/v1"
@@ -17,6 +21,25 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+const (
+	validatorWebhookName = "capp-validator"
+
+	reasonDecodeError                = "DecodeError"
+	reasonCappConfigUnavailable      = "CappConfigUnavailable"
+	reasonInvalidHostname            = "InvalidHostname"
+	reasonHostnameCheckError         = "HostnameCheckError"
+	reasonHostnameTaken              = "HostnameTaken"
+	reasonInvalidAdditionalHostnames = "InvalidAdditionalHostnames"
+	reasonInvalidPreviewHostname     = "InvalidPreviewHostname"
+	reasonInvalidLogSpec             = "InvalidLogSpec"
+	reasonLogDestinationCheckError   = "LogDestinationCheckError"
+	reasonLogDestinationTaken        = "LogDestinationTaken"
+	reasonInvalidTrafficTargets      = "InvalidTrafficTargets"
+	reasonInvalidSources             = "InvalidSources"
+	reasonInvalidRollout             = "InvalidRollout"
+	reasonInvalidScaleMetric         = "InvalidScaleMetric"
+)
+
 type CappValidator struct {
 	Client  client.Client
 	Decoder admission.Decoder
@@ -32,6 +55,7 @@ func (c *CappValidator) Handle(ctx context.Context, req admission.Request) admis
 	capp := cappv1alpha1.Capp{}
 	if err := c.Decoder.DecodeRaw(req.Object, &capp); err != nil {
 		logger.Error(err, "could not decode capp object")
+		metrics.RecordAdmission(validatorWebhookName, metrics.ResultErrored, reasonDecodeError)
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
@@ -40,6 +64,7 @@ func (c *CappValidator) Handle(ctx context.Context, req admission.Request) admis
 		err := c.Decoder.DecodeRaw(req.OldObject, oldCapp)
 		if err != nil {
 			logger.Error(err, "could not decode old capp object")
+			metrics.RecordAdmission(validatorWebhookName, metrics.ResultErrored, reasonDecodeError)
 			return admission.Errored(http.StatusBadRequest, err)
 		}
 	}
@@ -48,9 +73,9 @@ func (c *CappValidator) Handle(ctx context.Context, req admission.Request) admis
 }
 
 func (c *CappValidator) handle(ctx context.Context, capp cappv1alpha1.Capp, oldCapp *cappv1alpha1.Capp) admission.Response {
-	config, err := common.GetCappConfig(ctx, c.Client)
+	config, err := common.GetCappConfig(ctx, c.Client, capp.Namespace)
 	if err != nil {
-		return admission.Denied("Failed to fetch CappConfig")
+		return denied(reasonCappConfigUnavailable, "Failed to fetch CappConfig")
 	}
 
 	var allowedHostnamePatterns []string
@@ -60,30 +85,89 @@ func (c *CappValidator) handle(ctx context.Context, capp cappv1alpha1.Capp, oldC
 
 	if oldCapp == nil || capp.Spec.RouteSpec.Hostname != oldCapp.Spec.RouteSpec.Hostname {
 		if errs := common.ValidateDomainName(capp.Spec.RouteSpec.Hostname, allowedHostnamePatterns); errs != nil {
-			return admission.Denied(errs.Error())
+			return denied(reasonInvalidHostname, errs.Error())
 		}
 		taken, err := common.IsDomainNameTaken(capp.Spec.RouteSpec.Hostname)
 		if err != nil {
-			return admission.Denied(fmt.Sprintf("hostname check error: %v", err))
+			return denied(reasonHostnameCheckError, fmt.Sprintf("hostname check error: %v", err))
 		}
 		if taken {
-			return admission.Denied(fmt.Sprintf("invalid name %q: hostname must be unique and not already taken", capp.Spec.RouteSpec.Hostname))
+			return denied(reasonHostnameTaken, fmt.Sprintf("invalid name %q: hostname must be unique and not already taken", capp.Spec.RouteSpec.Hostname))
+		}
+	}
+
+	if errs := common.ValidateAdditionalHostnames(capp.Spec.RouteSpec, allowedHostnamePatterns); errs != nil {
+		return denied(reasonInvalidAdditionalHostnames, errs.Error())
+	}
+
+	for _, hostname := range capp.Spec.RouteSpec.AdditionalHostnames {
+		if oldCapp != nil && slices.ContainsFunc(oldCapp.Spec.RouteSpec.AdditionalHostnames, func(oldHostname cappv1alpha1.HostnameSpec) bool {
+			return oldHostname.Hostname == hostname.Hostname
+		}) {
+			continue
+		}
+		taken, err := common.IsDomainNameTaken(hostname.Hostname)
+		if err != nil {
+			return denied(reasonHostnameCheckError, fmt.Sprintf("hostname check error: %v", err))
+		}
+		if taken {
+			return denied(reasonHostnameTaken, fmt.Sprintf("invalid name %q: hostname must be unique and not already taken", hostname.Hostname))
+		}
+	}
+
+	for _, previewRoute := range utils.GetPreviewRoutes(capp.Spec.RouteSpec, rollout.GetTrafficTargets(capp)) {
+		if errs := common.ValidateDomainName(previewRoute.Hostname, allowedHostnamePatterns); errs != nil {
+			return denied(reasonInvalidPreviewHostname, fmt.Sprintf("invalid preview hostname of tag %q: %s", previewRoute.Tag, errs.Error()))
 		}
 	}
 
 	if capp.Spec.LogSpec != (cappv1alpha1.LogSpec{}) {
-		if errs := common.ValidateLogSpec(capp.Spec.LogSpec); errs != nil {
-			return admission.Denied(errs.Error())
+		if errs := common.ValidateLogSpec(capp.Spec.LogSpec, config.Spec.LogConfig.DefaultIndex); errs != nil {
+			return denied(reasonInvalidLogSpec, errs.Error())
+		}
+	}
+
+	if errs := common.ValidateLogDestinations(capp.Name, capp.Spec.LogDestinations, config.Spec.LogConfig.DefaultIndex); errs != nil {
+		return denied(reasonInvalidLogSpec, errs.Error())
+	}
+
+	for _, destination := range utils.GetLogDestinations(capp) {
+		taken, err := common.IsLogDestinationNameTaken(ctx, c.Client, capp, destination)
+		if err != nil {
+			return denied(reasonLogDestinationCheckError, fmt.Sprintf("log destination check error: %v", err))
+		}
+		if taken {
+			name := utils.GetLogDestinationResourceName(capp.Name, destination.Name)
+			return denied(reasonLogDestinationTaken, fmt.Sprintf("invalid log destination %q: output %q already exists and does not belong to the Capp", destination.Name, name))
 		}
 	}
 
+	if errs := common.ValidateTrafficTargets(utils.GetTrafficTargets(capp.Spec.RouteSpec)); errs != nil {
+		return denied(reasonInvalidTrafficTargets, errs.Error())
+	}
+
+	if errs := common.ValidateSources(capp.Spec.Sources); errs != nil {
+		return denied(reasonInvalidSources, errs.Error())
+	}
+
+	if errs := common.ValidateRollout(capp.Spec.Rollout, capp.Spec.RouteSpec); errs != nil {
+		return denied(reasonInvalidRollout, errs.Error())
+	}
+
 	if len(capp.Spec.Sources) > 0 && capp.Spec.ScaleMetric != "external" {
-		return admission.Denied(fmt.Sprintf("invalid scale metric %q: must be 'external' when sources are defined", capp.Spec.ScaleMetric))
+		return denied(reasonInvalidScaleMetric, fmt.Sprintf("invalid scale metric %q: must be 'external' when sources are defined", capp.Spec.ScaleMetric))
 	}
 
 	if capp.Spec.ScaleMetric == "external" && len(capp.Spec.Sources) == 0 {
-		return admission.Denied("invalid scale metric 'external': must have at least one source defined")
+		return denied(reasonInvalidScaleMetric, "invalid scale metric 'external': must have at least one source defined")
 	}
 
+	metrics.RecordAdmission(validatorWebhookName, metrics.ResultAllowed, "")
 	return admission.Allowed("")
 }
+
+// denied returns a response denying the request with the given message, and records the reason of the denial.
+func denied(reason, message string) admission.Response {
+	metrics.RecordAdmission(validatorWebhookName, metrics.ResultDenied, reason)
+	return admission.Denied(message)
+}
@@ -40,7 +40,7 @@ func TestCappValidator_Handle(t *testing.T) {
 				Spec: cappv1alpha1.CappSpec{
 					ScaleMetric: "external",
 					Sources: []cappv1alpha1.KedaSource{
-						{Name: "test"},
+						{Name: "test", ScalarType: "kafka"},
 					},
 					RouteSpec: cappv1alpha1.RouteSpec{
 						Hostname: "valid-hostname.com",
@@ -60,7 +60,7 @@ func TestCappValidator_Handle(t *testing.T) {
 				Spec: cappv1alpha1.CappSpec{
 					ScaleMetric: "cpu",
 					Sources: []cappv1alpha1.KedaSource{
-						{Name: "test"},
+						{Name: "test", ScalarType: "kafka"},
 					},
 					RouteSpec: cappv1alpha1.RouteSpec{
 						Hostname: "valid-hostname.com",
@@ -169,6 +169,36 @@ var _ = Describe("Validate capp creation", func() {
 		Expect(secretTarget.Parameter).To(Equal(testconsts.AuthParameter))
 		Expect(secretTarget.SecretRef.Key).To(Equal(testconsts.KedaSecretKey))
 		Expect(secretTarget.SecretRef.Name).To(Equal(testconsts.KedaSecretName))
+
+		By("Checking if the ScaledObject and TriggerAuthentication were created successfully")
+		scaledObject := utilst.CreateScaledObjectObject(kedaSourceName, testCapp.Namespace)
+		Eventually(func() bool {
+			return utilst.DoesResourceExist(k8sClient, scaledObject)
+		}, testconsts.Timeout, testconsts.Interval).Should(BeTrue(), "Should find a resource.")
+
+		triggerAuth := utilst.CreateTriggerAuthObject(testconsts.TriggerAuthName, testCapp.Namespace)
+		Eventually(func() bool {
+			return utilst.DoesResourceExist(k8sClient, triggerAuth)
+		}, testconsts.Timeout, testconsts.Interval).Should(BeTrue(), "Should find a resource.")
+
+		By("Checking the source status is reported")
+		Eventually(func() []string {
+			capp := utilst.GetCapp(k8sClient, createdCapp.Name, createdCapp.Namespace)
+			var names []string
+			for _, sourceStatus := range capp.Status.SourceStatus {
+				names = append(names, sourceStatus.Name)
+			}
+			return names
+		}, testconsts.Timeout, testconsts.Interval).Should(ContainElement(kedaSourceName))
+
+		By("Deleting the Capp instance")
+		utilst.DeleteCapp(k8sClient, createdCapp)
+		Eventually(func() bool {
+			return utilst.DoesResourceExist(k8sClient, scaledObject)
+		}, testconsts.Timeout, testconsts.Interval).Should(BeFalse(), "Should not find a resource.")
+		Eventually(func() bool {
+			return utilst.DoesResourceExist(k8sClient, triggerAuth)
+		}, testconsts.Timeout, testconsts.Interval).Should(BeFalse(), "Should not find a resource.")
 	})
 
 })
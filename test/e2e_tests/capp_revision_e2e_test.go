@@ -52,6 +52,15 @@ var _ = Describe("Validate CappRevision creation", func() {
 		cappRevisionName = kmeta.ChildName(desiredCapp.Name, fmt.Sprintf("-%05d", 2))
 		utilst.GetCappRevision(k8sClient, cappRevisionName, desiredCapp.Namespace)
 
+		By("Checking the CappRevision status records the change")
+		Eventually(func() int {
+			return len(utilst.GetCappRevision(k8sClient, cappRevisionName, desiredCapp.Namespace).Status.Diff)
+		}, testconsts.Timeout, testconsts.Interval).ShouldNot(BeZero(), "Should record the diff against the previous CappRevision")
+
+		cappRevision := utilst.GetCappRevision(k8sClient, cappRevisionName, desiredCapp.Namespace)
+		Expect(cappRevision.Status.Author).ShouldNot(BeEmpty())
+		Expect(cappRevision.Status.PreviousRevisionNumber).Should(Equal(1))
+
 		By("Deleting Capp")
 		utilst.DeleteCapp(k8sClient, desiredCapp)
 		Eventually(func() int {
@@ -94,4 +103,63 @@ var _ = Describe("Validate CappRevision creation", func() {
 		}, testconsts.Timeout, testconsts.Interval).Should(BeNumerically("<=", revisionsToKeep),
 			fmt.Sprintf("Should limit to at most %s CappRevision", strconv.Itoa(revisionsToKeep)))
 	})
+
+	It("Should roll back a Capp to a previous CappRevision", func() {
+		baseCapp := mocks.CreateBaseCapp()
+
+		By("Creating Capp")
+		desiredCapp := utilst.CreateCapp(k8sClient, baseCapp)
+		Eventually(func() int {
+			cappRevisions, _ := utilst.GetCappRevisions(context.Background(), k8sClient, *desiredCapp)
+			return len(cappRevisions)
+		}, testconsts.Timeout, testconsts.Interval).Should(Equal(1), "Should create the first CappRevision")
+
+		By("Updating Capp")
+		err := retry.RetryOnConflict(utilst.NewRetryOnConflictBackoff(), func() error {
+			desiredCapp = utilst.GetCapp(k8sClient, desiredCapp.Name, desiredCapp.Namespace)
+			desiredCapp.Spec.State = testconsts.DisabledState
+			return utilst.UpdateResource(k8sClient, desiredCapp)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() int {
+			cappRevisions, _ := utilst.GetCappRevisions(context.Background(), k8sClient, *desiredCapp)
+			return len(cappRevisions)
+		}, testconsts.Timeout, testconsts.Interval).Should(Equal(2), "Should create a second CappRevision")
+
+		By("Requesting a rollback to the first CappRevision")
+		err = retry.RetryOnConflict(utilst.NewRetryOnConflictBackoff(), func() error {
+			desiredCapp = utilst.GetCapp(k8sClient, desiredCapp.Name, desiredCapp.Namespace)
+			desiredCapp.Annotations[testconsts.RollbackAnnotationKey] = "1"
+			return utilst.UpdateResource(k8sClient, desiredCapp)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() string {
+			return utilst.GetCapp(k8sClient, desiredCapp.Name, desiredCapp.Namespace).Spec.State
+		}, testconsts.Timeout, testconsts.Interval).Should(Equal(testconsts.EnabledState), "Should restore the spec of the first CappRevision")
+
+		Eventually(func() string {
+			return utilst.GetCapp(k8sClient, desiredCapp.Name, desiredCapp.Namespace).Status.RollbackStatus.Result
+		}, testconsts.Timeout, testconsts.Interval).Should(Equal("Succeeded"), "Should report the rollback in the status")
+
+		Expect(utilst.GetCapp(k8sClient, desiredCapp.Name, desiredCapp.Namespace).Annotations).ShouldNot(HaveKey(testconsts.RollbackAnnotationKey))
+
+		Eventually(func() int {
+			cappRevisions, _ := utilst.GetCappRevisions(context.Background(), k8sClient, *desiredCapp)
+			return len(cappRevisions)
+		}, testconsts.Timeout, testconsts.Interval).Should(Equal(3), "Should record the rollback as a new CappRevision")
+
+		By("Requesting a rollback to a CappRevision which does not exist")
+		err = retry.RetryOnConflict(utilst.NewRetryOnConflictBackoff(), func() error {
+			desiredCapp = utilst.GetCapp(k8sClient, desiredCapp.Name, desiredCapp.Namespace)
+			desiredCapp.Annotations[testconsts.RollbackAnnotationKey] = "100"
+			return utilst.UpdateResource(k8sClient, desiredCapp)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() string {
+			return utilst.GetCapp(k8sClient, desiredCapp.Name, desiredCapp.Namespace).Status.RollbackStatus.Result
+		}, testconsts.Timeout, testconsts.Interval).Should(Equal("Failed"), "Should report the failed rollback in the status")
+	})
 })
@@ -6,6 +6,8 @@ import (
 	"github.com/dana-team/container-app-operator/test/e2e_tests/testconsts"
 	utilst "github.com/dana-team/container-app-operator/test/e2e_tests/utils"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+	knativev1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -139,4 +141,88 @@ var _ = Describe("Validate DomainMapping functionality", func() {
 			return capp.Status.RouteStatus
 		}, testconsts.Timeout, testconsts.Interval).Should(Equal(cappv1alpha1.RouteStatus{}), "Should update Route Status of Capp")
 	})
+
+	It("Should expose tagged revisions without traffic on preview DomainMappings", func() {
+		By("Creating a capp with a route")
+		createdCapp, routeHostname := utilst.CreateCappWithHTTPHostname(k8sClient)
+
+		By("Adding a tagged traffic target without traffic")
+		err := retry.RetryOnConflict(utilst.NewRetryOnConflictBackoff(), func() error {
+			toBeUpdatedCapp := utilst.GetCapp(k8sClient, createdCapp.Name, createdCapp.Namespace)
+			toBeUpdatedCapp.Spec.RouteSpec.TrafficTargets = []knativev1.TrafficTarget{
+				{LatestRevision: ptr.To(true), Percent: ptr.To(int64(100))},
+				{LatestRevision: ptr.To(true), Percent: ptr.To(int64(0)), Tag: testconsts.PreviewTag},
+			}
+
+			return utilst.UpdateResource(k8sClient, toBeUpdatedCapp)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Checking if the preview domainMapping was created successfully")
+		previewHostname := testconsts.PreviewTag + "-" + routeHostname
+		previewDomainMappingName := utilst.GenerateResourceName(previewHostname, testconsts.ZoneValue)
+		previewDomainMappingObject := mocks.CreateDomainMappingObject(previewDomainMappingName)
+		Eventually(func() bool {
+			return utilst.DoesResourceExist(k8sClient, previewDomainMappingObject)
+		}, testconsts.Timeout, testconsts.Interval).Should(BeTrue(), "Should find a resource.")
+
+		domainMapping := utilst.GetDomainMapping(k8sClient, previewDomainMappingName, createdCapp.Namespace)
+		Expect(domainMapping.Spec.Ref.Name).Should(Equal(testconsts.PreviewTag + "-" + createdCapp.Name))
+
+		By("Checking if the preview URL was added to the RouteStatus of the Capp")
+		Eventually(func() []string {
+			capp := utilst.GetCapp(k8sClient, createdCapp.Name, createdCapp.Namespace)
+			var tags []string
+			for _, previewURL := range capp.Status.RouteStatus.PreviewURLs {
+				tags = append(tags, previewURL.Tag)
+			}
+			return tags
+		}, testconsts.Timeout, testconsts.Interval).Should(Equal([]string{testconsts.PreviewTag}), "Should update Route Status of Capp")
+
+		By("Removing the tag and checking the preview is removed from the status")
+		err = retry.RetryOnConflict(utilst.NewRetryOnConflictBackoff(), func() error {
+			toBeUpdatedCapp := utilst.GetCapp(k8sClient, createdCapp.Name, createdCapp.Namespace)
+			toBeUpdatedCapp.Spec.RouteSpec.TrafficTargets = nil
+
+			return utilst.UpdateResource(k8sClient, toBeUpdatedCapp)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() int {
+			capp := utilst.GetCapp(k8sClient, createdCapp.Name, createdCapp.Namespace)
+			return len(capp.Status.RouteStatus.PreviewURLs)
+		}, testconsts.Timeout, testconsts.Interval).Should(BeZero(), "Should update Route Status of Capp")
+	})
+
+	It("Should create a DomainMapping for every additional hostname of a Capp", func() {
+		By("Creating a capp with a route")
+		createdCapp, routeHostname := utilst.CreateCappWithHTTPHostname(k8sClient)
+
+		By("Adding an additional hostname")
+		additionalHostname := utilst.GenerateRouteHostname()
+		err := retry.RetryOnConflict(utilst.NewRetryOnConflictBackoff(), func() error {
+			toBeUpdatedCapp := utilst.GetCapp(k8sClient, createdCapp.Name, createdCapp.Namespace)
+			toBeUpdatedCapp.Spec.RouteSpec.AdditionalHostnames = []cappv1alpha1.HostnameSpec{{Hostname: additionalHostname}}
+
+			return utilst.UpdateResource(k8sClient, toBeUpdatedCapp)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Checking if the domainMapping of the additional hostname was created successfully")
+		additionalDomainMappingName := utilst.GenerateResourceName(additionalHostname, testconsts.ZoneValue)
+		additionalDomainMappingObject := mocks.CreateDomainMappingObject(additionalDomainMappingName)
+		Eventually(func() bool {
+			return utilst.DoesResourceExist(k8sClient, additionalDomainMappingObject)
+		}, testconsts.Timeout, testconsts.Interval).Should(BeTrue(), "Should find a resource.")
+
+		By("Checking if the status of every hostname is reported in the RouteStatus of the Capp")
+		Eventually(func() []string {
+			capp := utilst.GetCapp(k8sClient, createdCapp.Name, createdCapp.Namespace)
+			var hostnames []string
+			for _, hostnameStatus := range capp.Status.RouteStatus.HostnamesStatus {
+				hostnames = append(hostnames, hostnameStatus.Hostname)
+			}
+			return hostnames
+		}, testconsts.Timeout, testconsts.Interval).Should(Equal([]string{routeHostname, additionalHostname}), "Should update Route Status of Capp")
+	})
 })
@@ -43,6 +43,7 @@ const (
 	SecretValue                     = "YmFyCg=="
 	ControllerNS                    = "container-app-operator-system"
 	ZoneValue                       = "capp-zone.com."
+	PreviewTag                      = "preview"
 	CappBaseImage                   = "ghcr.io/dana-team/capp-gin-app:v0.2.0"
 	PassEnvName                     = "PASSWORD"
 	ElasticType                     = "elastic"
@@ -76,6 +77,7 @@ var (
 	ManagedByLabelKey          = CappAPIGroup + "/managed-by"
 	LastUpdatedByAnnotationKey = CappAPIGroup + "/last-updated-by"
 	CappNameLabelKey           = CappAPIGroup + "/cappName"
+	RollbackAnnotationKey      = CappAPIGroup + "/rollback-to"
 	MinReplicas                = pointer.Int32(0)
 	MaxReplicas                = pointer.Int32(2)
 )